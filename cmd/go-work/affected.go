@@ -0,0 +1,142 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/go-mate/go-work/internal/gitdiff"
+	"github.com/go-mate/go-work/workgraph"
+	"github.com/go-mate/go-work/workspath"
+	"github.com/spf13/cobra"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/tern"
+	"github.com/yyle88/zaplog"
+)
+
+// newAffectedCmd creates affected subcommand to list modules touched by a change and their dependents
+// Changed files come from git diff since a ref, or from a file list, with "-" meaning stdin
+//
+// newAffectedCmd 创建 affected 子命令，列举受变更影响的模块及其依赖方
+// 变更文件来自自某个 ref 以来的 git diff，或来自文件列表，"-" 表示 stdin
+func newAffectedCmd(workPath string, flags *rootFlags) *cobra.Command {
+	var since string
+	var filesPath string
+	cmd := &cobra.Command{
+		Use:   "affected",
+		Short: "List modules affected by changed files",
+		Long:  "Maps changed files to their owning modules, then adds every workspace module depending on them",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			var files []string
+			switch {
+			case since != "" && filesPath != "":
+				fatal("--since and --files are mutually exclusive")
+			case since != "":
+				changed, err := gitdiff.ChangedFiles(cmd.Context(), workPath, since)
+				if err != nil {
+					fatal(err)
+				}
+				files = changed
+			case filesPath != "":
+				files = readFileList(workPath, filesPath)
+			default:
+				fatal("either --since or --files is required")
+			}
+			showAffected(cmd, workPath, flags, files)
+		},
+	}
+	cmd.Flags().StringVar(&since, "since", "", "git ref to compare the working tree with, like origin/main")
+	cmd.Flags().StringVar(&filesPath, "files", "", "file listing changed paths one per line, - reads stdin")
+	return cmd
+}
+
+// readFileList reads changed paths from the file at path, or from stdin when path is "-"
+// readFileList 从 path 处的文件读取变更路径，path 为 "-" 时从 stdin 读取
+func readFileList(workPath string, path string) []string {
+	var reader io.Reader = os.Stdin
+	if path != "-" {
+		file := rese.P1(os.Open(path))
+		defer rese.F0(file.Close)
+		reader = file
+	}
+	return rese.V1(gitdiff.ReadFiles(reader, workPath))
+}
+
+// showAffected prints the modules owning files together with their dependents
+// Rows match the main listing, with a reason telling changed modules from dependents
+//
+// showAffected 输出拥有 files 的模块及其依赖方
+// 行与主列表一致，并带有区分变更模块和依赖方的原因
+func showAffected(cmd *cobra.Command, workPath string, flags *rootFlags, files []string) {
+	modules := getModules(cmd.Context(), workPath, flags)
+	graph := workgraph.New(modules)
+
+	// Files and module DIRs are compared as real paths, git reports those even when the workspace is reached through a symlink
+	// 文件和模块 DIR 以真实路径比较，即使通过符号链接进入工作区，git 报告的也是真实路径
+	realModules := make([]workspath.Module, 0, len(modules))
+	for _, module := range modules {
+		module.Path = realPath(module.Path)
+		realModules = append(realModules, module)
+	}
+	ownerGraph := workgraph.New(realModules)
+
+	changed := map[string]bool{}
+	var owners []string
+	for _, file := range files {
+		owner, ok := ownerGraph.Owner(realPath(file))
+		if !ok {
+			zaplog.SUG.Debugln("outside workspace modules:", file)
+			continue
+		}
+		if !changed[owner] {
+			changed[owner] = true
+			owners = append(owners, owner)
+		}
+	}
+	affected := map[string]bool{}
+	for _, modulePath := range graph.Dependents(owners...) {
+		affected[modulePath] = true
+	}
+
+	type Result struct {
+		Path   string `json:"path"`
+		Module string `json:"module"`
+		Reason string `json:"reason"`
+		Dir    string `json:"-"` // Same as Path, for templates like {{.Dir}} // 与 Path 相同，供 {{.Dir}} 这类模板使用
+	}
+	results := []*Result{}
+	for _, module := range modules {
+		if !affected[module.ModulePath] {
+			continue
+		}
+		// Repeated module paths map to the first module only, like in the graph
+		// 重复的模块路径只对应第一个模块，与依赖图一致
+		if found, _ := graph.Module(module.ModulePath); found.Path != module.Path {
+			continue
+		}
+		results = append(results, &Result{
+			Path:   module.Path,
+			Module: module.ModulePath,
+			Reason: tern.BVV(changed[module.ModulePath], "changed", "dependent"),
+			Dir:    module.Path,
+		})
+	}
+	flags.write(results)
+}
+
+// realPath resolves the symlinks in path, for a missing path it resolves the nearest existing parent
+// Deleted files thus still resolve into the real DIR of their module
+//
+// realPath 解析 path 中的符号链接，path 不存在时解析最近的已存在父 DIR
+// 因此已删除的文件仍能解析到其模块的真实 DIR 中
+func realPath(path string) string {
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		return real
+	}
+	parent := filepath.Dir(path)
+	if parent == path {
+		return path
+	}
+	return filepath.Join(realPath(parent), filepath.Base(path))
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-mate/go-work/internal/testfs"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+// TestAffected_MovedFileThroughSymlink tests a file moved across modules marks both,
+// with go-work started in the workspace through a symlink
+//
+// TestAffected_MovedFileThroughSymlink 测试跨模块移动的文件会标记两个模块，
+// 且 go-work 通过符号链接在工作区中启动
+func TestAffected_MovedFileThroughSymlink(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	repo := rese.V1(filepath.EvalSymlinks(t.TempDir()))
+	testfs.WriteGoMod(t, filepath.Join(repo, "a"), "ex.com/a", "")
+	testfs.WriteFile(t, filepath.Join(repo, "a", "a.go"), "package a\n")
+	testfs.WriteFile(t, filepath.Join(repo, "a", "moved.go"), "package a\n\n// Moved keeps its content so git sees a rename\nvar Moved = 1\n")
+	testfs.WriteGoMod(t, filepath.Join(repo, "b"), "ex.com/b", "")
+	testfs.WriteFile(t, filepath.Join(repo, "b", "b.go"), "package b\n")
+	testfs.RunGit(t, repo, "init", "-q")
+	testfs.RunGit(t, repo, "add", "-A")
+	testfs.RunGit(t, repo, "commit", "-q", "-m", "base")
+	testfs.RunGit(t, repo, "mv", filepath.Join("a", "moved.go"), filepath.Join("b", "moved.go"))
+
+	link := filepath.Join(t.TempDir(), "link")
+	require.NoError(t, os.Symlink(repo, link))
+
+	var results []struct {
+		Module string `json:"module"`
+		Reason string `json:"reason"`
+	}
+	require.NoError(t, json.Unmarshal(runGoWork(t, link, "affected", "--since", "HEAD"), &results))
+	require.Len(t, results, 2)
+	require.Equal(t, "ex.com/a", results[0].Module)
+	require.Equal(t, "changed", results[0].Reason)
+	require.Equal(t, "ex.com/b", results[1].Module)
+	require.Equal(t, "changed", results[1].Reason)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/go-mate/go-work/workspace"
+	"github.com/go-mate/go-work/workspath"
+	"github.com/spf13/cobra"
+)
+
+// newCheckCmd creates check subcommand to detect drift between go.work and modules on disk
+// Also reports go.mod replaces pointing inside the workspace, which break the module once released
+// Exits with status 1 when anything is found, so CI can fail on it
+//
+// newCheckCmd 创建 check 子命令，检测 go.work 与磁盘上模块之间的偏差
+// 同时报告指向工作区内部的 go.mod replace，这类 replace 会使发布后的模块无法使用
+// 发现任何问题时以状态码 1 退出，使 CI 可以据此失败
+func newCheckCmd(workPath string, flags *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "check",
+		Short: "Check go.work against modules on disk",
+		Long:  "Reports modules missing from go.work, use directives pointing at DIRs without go.mod and go.mod replaces pointing inside the workspace, exits 1 on any",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if runCheck(cmd.Context(), workPath, flags) {
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+// checkResult is one finding of the check subcommand
+// checkResult 是 check 子命令的一条发现
+type checkResult struct {
+	Kind    string `json:"kind"`              // Kind of finding: added, missing, stale or replace // 发现类型：added、missing、stale 或 replace
+	Dir     string `json:"dir"`               // Absolute module DIR // 模块的绝对 DIR
+	Use     string `json:"use,omitempty"`     // Use path as written in go.work // go.work 中书写的 use 路径
+	Module  string `json:"module,omitempty"`  // Module path declared in go.mod // go.mod 中声明的模块路径
+	Replace string `json:"replace,omitempty"` // Local replace as written in go.mod // go.mod 中书写的本地 replace
+}
+
+// runCheck prints the drift and local replaces on stdout and reports whether any was found
+// runCheck 将偏差和本地 replace 输出到 stdout 并返回是否发现问题
+func runCheck(ctx context.Context, workPath string, flags *rootFlags) bool {
+	ws, _ := loadGoWork(workPath)
+	modules := getModules(ctx, workPath, flags)
+	res := workspace.Diff(modules, ws.Uses)
+
+	results := []*checkResult{}
+	for _, group := range []struct {
+		kind    string
+		entries []workspace.DiffEntry
+	}{
+		{"added", res.Added},
+		{"missing", res.Missing},
+		{"stale", res.Stale},
+	} {
+		for _, entry := range group.entries {
+			results = append(results, &checkResult{
+				Kind:   group.kind,
+				Dir:    entry.Dir,
+				Use:    entry.Use,
+				Module: entry.Module,
+			})
+		}
+	}
+	replaces := workspath.LocalReplaces(modules, workPath)
+	for _, rep := range replaces {
+		results = append(results, &checkResult{
+			Kind:    "replace",
+			Dir:     rep.Dir,
+			Module:  rep.Module,
+			Replace: rep.Path + " => " + rep.Target,
+		})
+	}
+	flags.write(results)
+	return res.HasDrift() || len(replaces) > 0
+}
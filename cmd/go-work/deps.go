@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/go-mate/go-work/internal/output"
+	"github.com/go-mate/go-work/internal/runner"
+	"github.com/go-mate/go-work/internal/udiff"
+	"github.com/go-mate/go-work/workdeps"
+	"github.com/go-mate/go-work/workspath"
+	"github.com/spf13/cobra"
+	"github.com/yyle88/must"
+	"github.com/yyle88/zaplog"
+	"golang.org/x/mod/module"
+)
+
+// newDepsCmd creates deps subcommand grouping the dependency tools
+// newDepsCmd 创建 deps 子命令，汇集依赖相关的工具
+func newDepsCmd(workPath string, flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deps",
+		Short: "Inspect and update dependencies across modules",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newDepsAlignCmd(workPath, flags))
+	cmd.AddCommand(newDepsSetCmd(workPath, flags))
+	return cmd
+}
+
+// newDepsAlignCmd creates deps align subcommand to report and fix dependency version drift
+// newDepsAlignCmd 创建 deps align 子命令，报告并修复依赖版本偏差
+func newDepsAlignCmd(workPath string, flags *rootFlags) *cobra.Command {
+	var mismatched bool
+	var fix bool
+	var pins []string
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "align",
+		Short: "Report dependencies required at different versions",
+		Long:  "Lists each external dependency with its versions and the modules using them, --fix moves lagging modules to the highest or pinned version",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			deps := workdeps.Collect(getModules(cmd.Context(), workPath, flags))
+			if !fix {
+				showDeps(deps, flags, mismatched)
+				return
+			}
+			updates := workdeps.Align(deps, parsePins(pins))
+			changes, err := workdeps.Apply(updates)
+			if err != nil {
+				fatal(err)
+			}
+			if !dryRun {
+				for _, update := range updates {
+					zaplog.SUG.Infoln("align:", update.Module, update.Path, update.From, "=>", update.To)
+				}
+			}
+			applyChanges(workPath, changes, dryRun)
+		},
+	}
+	cmd.Flags().BoolVar(&mismatched, "mismatched", false, "list only dependencies required at more than one version")
+	cmd.Flags().BoolVar(&fix, "fix", false, "rewrite go.mod files so each dependency uses one version")
+	cmd.Flags().StringSliceVar(&pins, "pin", nil, "target version of a dependency with --fix, as path@version")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print a unified diff instead of writing go.mod files")
+	return cmd
+}
+
+// showDeps prints one row per dependency version with the modules requiring it
+// showDeps 每个依赖版本输出一行，并列出依赖它的模块
+func showDeps(deps []*workdeps.Dependency, flags *rootFlags, mismatched bool) {
+	type Result struct {
+		Path     string   `json:"path"`
+		Version  string   `json:"version"`
+		Mismatch bool     `json:"mismatch"`
+		Modules  []string `json:"modules"`
+	}
+	results := []*Result{}
+	for _, dep := range deps {
+		if mismatched && !dep.Mismatched() {
+			continue
+		}
+		for _, version := range dep.Versions {
+			res := &Result{Path: dep.Path, Version: version, Mismatch: dep.Mismatched(), Modules: []string{}}
+			for _, usage := range dep.Usages {
+				if usage.Version == version {
+					res.Modules = append(res.Modules, usage.Module)
+				}
+			}
+			results = append(results, res)
+		}
+	}
+	flags.write(results)
+}
+
+// newDepsSetCmd creates deps set subcommand to bump one dependency in every module requiring it
+// newDepsSetCmd 创建 deps set 子命令，在每个依赖某依赖项的模块中更新其版本
+func newDepsSetCmd(workPath string, flags *rootFlags) *cobra.Command {
+	var dryRun bool
+	var tidy bool
+	cmd := &cobra.Command{
+		Use:   "set <module>@<version>",
+		Short: "Set a dependency version in every module requiring it",
+		Long:  "Rewrites the require directive of the dependency in each go.mod already requiring it, then prints the touched files",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			path, version := splitModuleVersion(args[0])
+			modules := getModules(cmd.Context(), workPath, flags)
+			updates := workdeps.Set(workdeps.Collect(modules), path, version)
+			if len(updates) == 0 {
+				zaplog.SUG.Infoln("no module requires", path, "at another version")
+				return
+			}
+			changes, err := workdeps.Apply(updates)
+			if err != nil {
+				fatal(err)
+			}
+			applyChanges(workPath, changes, dryRun)
+			showUpdates(workPath, updates)
+			if tidy && !dryRun {
+				runTidy(cmd, updates)
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print a unified diff instead of writing go.mod files")
+	cmd.Flags().BoolVar(&tidy, "tidy", false, "run go mod tidy in each touched module afterwards")
+	return cmd
+}
+
+// showUpdates prints a summary table of the touched go.mod files on stderr
+// showUpdates 在 stderr 输出被修改的 go.mod 文件的汇总表
+func showUpdates(workPath string, updates []*workdeps.Update) {
+	type Result struct {
+		Module string `json:"module"`
+		File   string `json:"file"`
+		From   string `json:"from"`
+		To     string `json:"to"`
+	}
+	results := make([]*Result, 0, len(updates))
+	for _, update := range updates {
+		results = append(results, &Result{
+			Module: update.Module,
+			File:   relName(workPath, filepath.Join(update.Dir, "go.mod")),
+			From:   update.From,
+			To:     update.To,
+		})
+	}
+	must.Done(output.Write(os.Stderr, output.Table, results))
+}
+
+// runTidy runs go mod tidy in the DIR of each update, exiting with status 1 when any fails
+// runTidy 在每个更新的 DIR 中运行 go mod tidy，任一失败时以状态码 1 退出
+func runTidy(cmd *cobra.Command, updates []*workdeps.Update) {
+	var tasks []runner.Task
+	seen := map[string]bool{}
+	for _, update := range updates {
+		if !seen[update.Dir] {
+			seen[update.Dir] = true
+			tasks = append(tasks, runner.Task{Dir: update.Dir, Label: update.Module})
+		}
+	}
+	results := runner.Run(cmd.Context(), tasks, "go", []string{"mod", "tidy"}, runner.Options{
+		Parallel:  runtime.NumCPU(),
+		KeepGoing: true,
+		Stdout:    os.Stdout,
+		Stderr:    os.Stderr,
+	})
+	for _, res := range results {
+		if res.Status != runner.StatusOK {
+			fatal("go mod tidy failed:", res.Task.Label, res.Err)
+		}
+	}
+}
+
+// splitModuleVersion splits path@version, exiting when either part is malformed
+// splitModuleVersion 拆分 path@version，任一部分格式错误时退出
+func splitModuleVersion(arg string) (string, string) {
+	path, version, ok := strings.Cut(arg, "@")
+	if !ok || module.CheckPath(path) != nil || !isVersion(version) {
+		fatal("invalid argument, expect path@version:", arg)
+	}
+	return path, version
+}
+
+// parsePins parses path@version pins into a map, exiting on malformed ones
+// parsePins 将 path@version 形式的固定版本解析为映射，格式错误时退出
+func parsePins(pins []string) map[string]string {
+	res := map[string]string{}
+	for _, pin := range pins {
+		path, version := splitModuleVersion(pin)
+		res[path] = version
+	}
+	return res
+}
+
+// isVersion reports whether version is a canonical module version like v1.2.3
+// isVersion 判断 version 是否为 v1.2.3 这样的规范模块版本
+func isVersion(version string) bool {
+	return module.CanonicalVersion(version) == version && version != ""
+}
+
+// applyChanges writes the changed files, or prints them as unified diffs with dryRun
+// Paths in the diff and log are relative to workPath
+//
+// applyChanges 写入变更的文件，dryRun 时改为输出统一 diff
+// diff 和日志中的路径相对 workPath
+func applyChanges(workPath string, changes []*workspath.Change, dryRun bool) {
+	for _, change := range changes {
+		if !change.Changed() {
+			continue
+		}
+		name := relName(workPath, change.Path)
+		if dryRun {
+			fmt.Print(udiff.Unified("a/"+name, "b/"+name, change.Old, change.New))
+			continue
+		}
+		must.Done(change.Write())
+		zaplog.SUG.Infoln("updated:", name)
+	}
+}
+
+// relName returns path relative to workPath in slash form, or path itself when it is not below workPath
+// relName 以斜杠形式返回 path 相对 workPath 的路径，不在 workPath 之下时返回 path 本身
+func relName(workPath string, path string) string {
+	rel, err := filepath.Rel(workPath, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
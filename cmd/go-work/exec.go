@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/go-mate/go-work/internal/output"
+	"github.com/go-mate/go-work/internal/runner"
+	"github.com/go-mate/go-work/workspath"
+	"github.com/spf13/cobra"
+	"github.com/yyle88/must"
+)
+
+// newExecCmd creates exec subcommand to run a command in every discovered module
+// Prints a summary table on stderr and exits with status 1 when any module fails
+//
+// newExecCmd 创建 exec 子命令，在每个发现的模块中运行命令
+// 在 stderr 打印汇总表，任一模块失败时以状态码 1 退出
+func newExecCmd(workPath string, flags *rootFlags) *cobra.Command {
+	opts := runner.Options{Stdout: os.Stdout, Stderr: os.Stderr}
+	cmd := &cobra.Command{
+		Use:   "exec [flags] -- <cmd> [args...]",
+		Short: "Run a command in every discovered module",
+		Long:  "Runs the command with each module root as working DIR, then prints the exit status of each module",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if !runExec(cmd, workPath, flags, args, opts) {
+				os.Exit(1)
+			}
+		},
+	}
+	// Flags after the command name belong to the command, so "go-work exec go test -v" works without "--"
+	// 命令名之后的标志属于该命令，因此 "go-work exec go test -v" 无需 "--" 即可运行
+	cmd.Flags().SetInterspersed(false)
+	cmd.Flags().IntVar(&opts.Parallel, "parallel", runtime.NumCPU(), "modules to run at once")
+	cmd.Flags().BoolVar(&opts.KeepGoing, "keep-going", false, "keep starting modules after a failure")
+	cmd.Flags().BoolVar(&opts.Grouped, "grouped", false, "print the output of each module in one piece instead of prefixed lines")
+	return cmd
+}
+
+// execResult is the summary row of one module
+// execResult 是单个模块的汇总行
+type execResult struct {
+	Module   string `json:"module"`   // Module path // 模块路径
+	Dir      string `json:"dir"`      // Module DIR relative to the working path // 相对工作路径的模块 DIR
+	Status   string `json:"status"`   // ok, failed or skipped // ok、failed 或 skipped
+	Exit     int    `json:"exit"`     // Exit code of the command // 命令的退出码
+	Duration string `json:"duration"` // Run time of the command // 命令的运行时长
+}
+
+// runExec runs args in each module and reports whether all of them succeeded
+// With --topo the modules run level by level, so dependencies finish before their dependents
+//
+// runExec 在每个模块中运行 args 并返回是否全部成功
+// 设置 --topo 时模块逐层运行，使依赖在依赖方之前完成
+func runExec(cmd *cobra.Command, workPath string, flags *rootFlags, args []string, opts runner.Options) bool {
+	modules := getModules(cmd.Context(), workPath, flags)
+	levels := [][]workspath.Module{modules}
+	if flags.topo {
+		levels = topoLevels(modules)
+	}
+
+	success := true
+	summary := make([]*execResult, 0, len(modules))
+	for _, level := range levels {
+		tasks := make([]runner.Task, 0, len(level))
+		for _, module := range level {
+			tasks = append(tasks, runner.Task{Dir: module.Path, Label: module.RelPath})
+		}
+
+		// Once a level fails, later levels are skipped unless --keep-going is set
+		// 某一层失败后，未设置 --keep-going 时跳过之后的层
+		var results []*runner.Result
+		if success || opts.KeepGoing {
+			results = runner.Run(cmd.Context(), tasks, args[0], args[1:], opts)
+		} else {
+			for _, task := range tasks {
+				results = append(results, &runner.Result{Task: task, Status: runner.StatusSkipped})
+			}
+		}
+
+		for idx, res := range results {
+			success = success && res.Status == runner.StatusOK
+			summary = append(summary, &execResult{
+				Module:   level[idx].ModulePath,
+				Dir:      res.Task.Label,
+				Status:   string(res.Status),
+				Exit:     res.ExitCode,
+				Duration: res.Duration.Round(time.Millisecond).String(),
+			})
+		}
+	}
+	must.Done(output.Write(os.Stderr, output.Table, summary))
+	return success
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-mate/go-work/internal/udiff"
+	"github.com/go-mate/go-work/workspace"
+	"github.com/spf13/cobra"
+	"github.com/yyle88/must"
+	"github.com/yyle88/osexistpath/osomitexist"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/zaplog"
+)
+
+// newInitCmd creates init subcommand to create go.work from discovered modules
+// newInitCmd 创建 init 子命令，根据发现的模块创建 go.work
+func newInitCmd(workPath string, flags *rootFlags) *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create go.work from discovered modules",
+		Long:  "Creates go.work in the current DIR with a use directive for each discovered module",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if osomitexist.IsFile(filepath.Join(workPath, "go.work")) {
+				fatal("go.work already exists, run sync to update it")
+			}
+			ws := &workspace.Workspace{WorkRoot: workPath}
+			writeGoWork(cmd.Context(), ws, flags, dryRun)
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print a unified diff instead of writing go.work")
+	return cmd
+}
+
+// newSyncCmd creates sync subcommand to update go.work with discovered modules
+// newSyncCmd 创建 sync 子命令，根据发现的模块更新 go.work
+func newSyncCmd(workPath string, flags *rootFlags) *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Update go.work with discovered modules",
+		Long:  "Rewrites the use directives of go.work to match discovered modules, keeping comments, godebug and replace directives",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			ws, useErrs := loadGoWork(workPath)
+			if useErrs != nil {
+				zaplog.SUG.Warnln("dropping invalid use directives:", useErrs.Error())
+			}
+			writeGoWork(cmd.Context(), ws, flags, dryRun)
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print a unified diff instead of writing go.work")
+	return cmd
+}
+
+// loadGoWork loads go.work of workPath along with its invalid use directives
+// Exits with a readable message when go.work is missing or cannot be parsed
+//
+// loadGoWork 加载 workPath 的 go.work 及其中无效的 use 指令
+// go.work 缺失或无法解析时以可读的消息退出
+func loadGoWork(workPath string) (*workspace.Workspace, workspace.UseErrors) {
+	ws, err := workspace.Load(workPath)
+	var useErrs workspace.UseErrors
+	switch {
+	case err == nil:
+		return ws, nil
+	case errors.As(err, &useErrs):
+		return ws, useErrs
+	case errors.Is(err, os.ErrNotExist):
+		fatal("no go.work in", workPath, "- run go-work init first")
+	default:
+		fatal("invalid go.work:", err)
+	}
+	return nil, nil
+}
+
+// writeGoWork sets the discovered modules as workspace projects and writes go.work
+// With dryRun the change is printed as a unified diff instead
+//
+// writeGoWork 将发现的模块设为工作区项目并写入 go.work
+// dryRun 时改为输出统一 diff
+func writeGoWork(ctx context.Context, ws *workspace.Workspace, flags *rootFlags, dryRun bool) {
+	ws.Projects = nil
+	for _, module := range getModules(ctx, ws.WorkRoot, flags) {
+		if module.File != nil {
+			ws.Projects = append(ws.Projects, module.Path)
+		}
+	}
+
+	workPath := filepath.Join(ws.WorkRoot, "go.work")
+	if dryRun {
+		var oldContent []byte
+		oldName := "/dev/null"
+		if osomitexist.IsFile(workPath) {
+			oldContent = rese.V1(os.ReadFile(workPath))
+			oldName = "a/go.work"
+		}
+		newContent := rese.V1(ws.FormatGoWork())
+		fmt.Print(udiff.Unified(oldName, "b/go.work", oldContent, newContent))
+		return
+	}
+	must.Done(ws.WriteGoWork())
+	zaplog.SUG.Infoln("wrote:", workPath)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+
+	"github.com/go-mate/go-work/workgraph"
+	"github.com/go-mate/go-work/workspath"
+	"github.com/spf13/cobra"
+	"github.com/yyle88/must"
+	"github.com/yyle88/zaplog"
+)
+
+// newGraphCmd creates graph subcommand to show dependencies between workspace modules
+// Takes the dot and mermaid formats besides the common ones, which print adjacency rows
+//
+// newGraphCmd 创建 graph 子命令，显示工作区模块之间的依赖
+// 除通用格式外还接受 dot 和 mermaid 格式，通用格式输出邻接行
+func newGraphCmd(workPath string, flags *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "graph",
+		Short: "Show dependencies between workspace modules",
+		Long:  "Links modules through go.mod require directives and local replace targets, use --format dot or mermaid to draw the graph",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			showGraph(newGraph(cmd, workPath, flags), flags)
+		},
+	}
+}
+
+// newGraph builds the dependency graph of the discovered modules, warning about cycles
+// newGraph 构建发现的模块的依赖图，并对环发出警告
+func newGraph(cmd *cobra.Command, workPath string, flags *rootFlags) *workgraph.Graph {
+	graph := workgraph.New(getModules(cmd.Context(), workPath, flags))
+	if _, err := graph.Sort(); err != nil {
+		zaplog.SUG.Warnln(err)
+	}
+	return graph
+}
+
+// topoLevels groups modules into topological levels, dependencies first
+// Modules outside the graph, like those without a valid go.mod or with a repeated module path, join the first level
+// Exits with the chain of each cycle when modules depend on each other
+//
+// topoLevels 将模块分组为拓扑层级，依赖在前
+// 不在图中的模块（例如没有有效 go.mod 或模块路径重复的模块）加入第一层
+// 模块相互依赖时输出每个环的边链并退出
+func topoLevels(modules []workspath.Module) [][]workspath.Module {
+	graph := workgraph.New(modules)
+	levels, err := graph.Levels()
+	if err != nil {
+		fatal(err)
+	}
+	var first []workspath.Module
+	for _, module := range modules {
+		if found, ok := graph.Module(module.ModulePath); !ok || found.Path != module.Path {
+			first = append(first, module)
+		}
+	}
+	results := make([][]workspath.Module, 0, len(levels))
+	for idx, level := range levels {
+		var items []workspath.Module
+		for _, modulePath := range level {
+			module, _ := graph.Module(modulePath)
+			items = append(items, module)
+		}
+		if idx == 0 {
+			items = append(items, first...)
+		}
+		results = append(results, items)
+	}
+	if len(results) == 0 && len(first) > 0 {
+		results = append(results, first)
+	}
+	return results
+}
+
+// showGraph prints the graph as DOT, Mermaid or adjacency rows
+// showGraph 以 DOT、Mermaid 或邻接行输出依赖图
+func showGraph(graph *workgraph.Graph, flags *rootFlags) {
+	switch flags.format {
+	case "dot":
+		must.Done(graph.WriteDot(os.Stdout))
+		return
+	case "mermaid":
+		must.Done(graph.WriteMermaid(os.Stdout))
+		return
+	}
+	type Result struct {
+		Module      string   `json:"module"`
+		Path        string   `json:"path"`
+		Deps        []string `json:"deps"`
+		ReverseDeps []string `json:"reverseDeps"`
+		Dir         string   `json:"-"` // Same as Path, for templates like {{.Dir}} // 与 Path 相同，供 {{.Dir}} 这类模板使用
+	}
+	results := []*Result{}
+	for _, module := range graph.Modules() {
+		results = append(results, &Result{
+			Module:      module.ModulePath,
+			Path:        module.Path,
+			Deps:        nonNil(graph.Deps(module.ModulePath)),
+			ReverseDeps: nonNil(graph.ReverseDeps(module.ModulePath)),
+			Dir:         module.Path,
+		})
+	}
+	flags.write(results)
+}
+
+// nonNil returns paths, or an empty slice when nil, so JSON shows [] instead of null
+// nonNil 返回 paths，为 nil 时返回空切片，使 JSON 显示 [] 而不是 null
+func nonNil(paths []string) []string {
+	if paths == nil {
+		return []string{}
+	}
+	return paths
+}
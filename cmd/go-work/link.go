@@ -0,0 +1,103 @@
+package main
+
+import (
+	"github.com/go-mate/go-work/workspath"
+	"github.com/spf13/cobra"
+	"github.com/yyle88/zaplog"
+)
+
+// newLinkCmd creates link subcommand to replace a module by the local DIR of another one
+// newLinkCmd 创建 link 子命令，将某个模块替换为另一个模块的本地 DIR
+func newLinkCmd(workPath string, flags *rootFlags) *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "link <module> <target>",
+		Short: "Add a relative replace from a module to another workspace module",
+		Long:  "Adds \"replace <target> => <relative DIR>\" to the go.mod of the module, both given as module path or path from the workspace root",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			modules := getModules(cmd.Context(), workPath, flags)
+			from := findModule(modules, args[0])
+			target := findModule(modules, args[1])
+			if from.Path == target.Path {
+				fatal("cannot link a module to itself:", args[0])
+			}
+			if !requires(from, target.ModulePath) {
+				zaplog.SUG.Warnln(from.ModulePath, "does not require", target.ModulePath, "so the replace has no effect yet")
+			}
+			change, err := workspath.Link(from, target)
+			if err != nil {
+				fatal(err)
+			}
+			applyChanges(workPath, []*workspath.Change{change}, dryRun)
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print a unified diff instead of writing go.mod files")
+	return cmd
+}
+
+// newUnlinkCmd creates unlink subcommand to drop local replaces between workspace modules
+// newUnlinkCmd 创建 unlink 子命令，删除工作区模块之间的本地 replace
+func newUnlinkCmd(workPath string, flags *rootFlags) *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "unlink [<module> [<target>]]",
+		Short: "Drop relative replaces between workspace modules",
+		Long:  "Drops filesystem replaces pointing inside the workspace, from every module without arguments, from the module with one, just the target with two",
+		Args:  cobra.MaximumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			modules := getModules(cmd.Context(), workPath, flags)
+			var froms []workspath.Module
+			if len(args) > 0 {
+				froms = []workspath.Module{findModule(modules, args[0])}
+			} else {
+				froms = modules
+			}
+			var changes []*workspath.Change
+			for _, from := range froms {
+				var paths []string
+				if len(args) == 2 {
+					paths = []string{findModule(modules, args[1]).ModulePath}
+				} else {
+					for _, rep := range workspath.LocalReplaces([]workspath.Module{from}, workPath) {
+						paths = append(paths, rep.Path)
+					}
+				}
+				if len(paths) == 0 {
+					continue
+				}
+				change, err := workspath.Unlink(from, paths...)
+				if err != nil {
+					fatal(err)
+				}
+				changes = append(changes, change)
+			}
+			applyChanges(workPath, changes, dryRun)
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print a unified diff instead of writing go.mod files")
+	return cmd
+}
+
+// findModule returns the module whose module path or path from the workspace root is name, exiting when none is
+// findModule 返回模块路径或相对工作区根的路径为 name 的模块，找不到时退出
+func findModule(modules []workspath.Module, name string) workspath.Module {
+	for _, module := range modules {
+		if module.ModulePath == name || module.RelPath == name {
+			return module
+		}
+	}
+	fatal("module not found in workspace:", name)
+	return workspath.Module{}
+}
+
+// requires reports whether module has a require of path
+// requires 判断 module 是否 require 了 path
+func requires(module workspath.Module, path string) bool {
+	for _, require := range module.Require {
+		if require.Path == path {
+			return true
+		}
+	}
+	return false
+}
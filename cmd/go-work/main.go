@@ -6,21 +6,112 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"strings"
+	"time"
 
+	"github.com/go-mate/go-work/internal/gover"
+	"github.com/go-mate/go-work/internal/modcache"
+	"github.com/go-mate/go-work/internal/output"
 	"github.com/go-mate/go-work/workspath"
 	"github.com/spf13/cobra"
 	"github.com/yyle88/must"
-	"github.com/yyle88/neatjson/neatjsons"
 	"github.com/yyle88/rese"
 	"github.com/yyle88/tern"
 	"github.com/yyle88/zaplog"
-	"golang.org/x/mod/modfile"
 )
 
+// rootFlags holds the persistent command-line flags shared by every subcommand
+// rootFlags 保存所有子命令共享的持久命令行标志
+type rootFlags struct {
+	excludes  []string      // Glob patterns of paths to skip // 需要跳过的路径 glob 模式
+	gitignore bool          // Skip paths ignored by git // 跳过被 git 忽略的路径
+	depth     int           // Deepest DIR level to walk // 遍历的最深 DIR 层级
+	noNested  bool          // Do not descend into found modules // 不进入已找到的模块
+	timeout   time.Duration // Stop scanning after this duration // 超过该时长后停止扫描
+	format    string        // Output format of results // 结果的输出格式
+	template  string        // Go template applied to each result // 应用到每条结果的 Go 模板
+	tmplFile  string        // File holding the Go template // 保存 Go 模板的文件
+	topo      bool          // Order modules with dependencies first // 按依赖在前的顺序排列模块
+}
+
+// options converts flags into workspath scan options
+// options 将标志转换为 workspath 扫描选项
+func (f *rootFlags) options() []workspath.Option {
+	opts := []workspath.Option{
+		workspath.WithCurrentProject(),
+		workspath.ScanDeep(),
+		workspath.SkipNoGo(),
+		workspath.WithExclude(f.excludes...),
+		workspath.WithMaxDepth(f.depth),
+	}
+	if f.gitignore {
+		opts = append(opts, workspath.RespectGitignore())
+	}
+	if f.noNested {
+		opts = append(opts, workspath.StopAtModule())
+	}
+	return opts
+}
+
+// packageOptions converts flags into workspath package scan options, unparsable files become scan errors
+// packageOptions 将标志转换为 workspath 包扫描选项，无法解析的文件成为扫描错误
+func (f *rootFlags) packageOptions() []workspath.Option {
+	opts := []workspath.Option{workspath.WithExclude(f.excludes...), workspath.ContinueOnError()}
+	if f.gitignore {
+		opts = append(opts, workspath.RespectGitignore())
+	}
+	return opts
+}
+
+// write renders result rows to stdout in the selected format
+// A given template takes precedence over the format
+//
+// write 以选定的格式将结果行输出到 stdout
+// 指定模板时优先于格式
+func (f *rootFlags) write(rows any) {
+	if f.template != "" || f.tmplFile != "" {
+		f.writeTemplate(rows)
+		return
+	}
+	format, err := output.Parse(f.format)
+	if err != nil {
+		fatal(err)
+	}
+	must.Done(output.Write(os.Stdout, format, rows))
+}
+
+// writeTemplate renders result rows to stdout through the template of --template or --template-file
+// writeTemplate 通过 --template 或 --template-file 的模板将结果行输出到 stdout
+func (f *rootFlags) writeTemplate(rows any) {
+	if f.template != "" && f.tmplFile != "" {
+		fatal("--template and --template-file are mutually exclusive")
+	}
+	text := f.template
+	if f.tmplFile != "" {
+		// Drop the final newline of the file, since each result already ends with one
+		// 去掉文件末尾的换行，因为每条结果已经以换行结束
+		text = strings.TrimSuffix(string(rese.V1(os.ReadFile(f.tmplFile))), "\n")
+	}
+	tmpl, err := output.ParseTemplate(rese.C1(os.Getwd()), text)
+	if err != nil {
+		fatal(err)
+	}
+	if err := output.WriteTemplate(os.Stdout, tmpl, rows); err != nil {
+		fatal(err)
+	}
+}
+
 func main() {
+	// Logs go to stderr so stdout carries just the results, ready for jq and other tools
+	// 日志输出到 stderr，使 stdout 只包含结果，可直接交给 jq 等工具
+	zaplog.SetLog(rese.P1(zaplog.NewZapLog(zaplog.NewConfig().SetOutputPaths([]string{"stderr"}))))
+
 	workPath := rese.C1(os.Getwd())
+	flags := &rootFlags{}
 
 	rootCmd := &cobra.Command{
 		Use:   "go-work",
@@ -28,84 +119,209 @@ func main() {
 		Long:  "go-work: Lists Go module paths in the current workspace",
 		Args:  cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
-			showPathList(workPath)
+			showPathList(cmd.Context(), workPath, flags)
 		},
 	}
+	rootCmd.PersistentFlags().StringSliceVar(&flags.excludes, "exclude", nil, "skip paths matching gitignore-style globs")
+	rootCmd.PersistentFlags().BoolVar(&flags.gitignore, "gitignore", false, "skip paths ignored by .gitignore files")
+	rootCmd.PersistentFlags().IntVar(&flags.depth, "depth", -1, "deepest DIR level to scan, negative means no limit")
+	rootCmd.PersistentFlags().BoolVar(&flags.noNested, "no-nested", false, "do not scan inside found modules")
+	rootCmd.PersistentFlags().DurationVar(&flags.timeout, "timeout", 0, "stop scanning after this duration, 0 means no limit")
+	rootCmd.PersistentFlags().StringVar(&flags.format, "format", string(output.JSON), "output format: json, ndjson, yaml, csv, tsv or table, graph also takes dot or mermaid")
+	rootCmd.PersistentFlags().StringVar(&flags.template, "template", "", "Go template applied to each result, like go list -f")
+	rootCmd.PersistentFlags().BoolVar(&flags.topo, "topo", false, "order modules with workspace dependencies first")
+	rootCmd.PersistentFlags().StringVar(&flags.tmplFile, "template-file", "", "file holding the Go template applied to each result")
+
+	rootCmd.AddCommand(newVersionCmd(workPath, flags))
+	rootCmd.AddCommand(newInitCmd(workPath, flags))
+	rootCmd.AddCommand(newSyncCmd(workPath, flags))
+	rootCmd.AddCommand(newCheckCmd(workPath, flags))
+	rootCmd.AddCommand(newExecCmd(workPath, flags))
+	rootCmd.AddCommand(newGraphCmd(workPath, flags))
+	rootCmd.AddCommand(newAffectedCmd(workPath, flags))
+	rootCmd.AddCommand(newDepsCmd(workPath, flags))
+	rootCmd.AddCommand(newLinkCmd(workPath, flags))
+	rootCmd.AddCommand(newUnlinkCmd(workPath, flags))
+	rootCmd.AddCommand(newReleaseCmd(workPath, flags))
+	rootCmd.AddCommand(newPackagesCmd(workPath, flags))
 
-	rootCmd.AddCommand(newVersionCmd(workPath))
-	must.Done(rootCmd.Execute())
+	// Cancel running scans on Ctrl+C
+	// 按下 Ctrl+C 时取消正在运行的扫描
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	must.Done(rootCmd.ExecuteContext(ctx))
 }
 
 // showPathList lists all Go module paths in workspace
 // showPathList 列举工作区中所有 Go 模块路径
-func showPathList(workPath string) {
+func showPathList(ctx context.Context, workPath string, flags *rootFlags) {
 	type Result struct {
 		Path   string `json:"path"`
 		Module string `json:"module"`
+		Dir    string `json:"-"` // Same as Path, for templates like {{.Dir}} // 与 Path 相同，供 {{.Dir}} 这类模板使用
 	}
 	var results []*Result
-	for _, path := range getModulePaths(workPath) {
-		modFile := parseModFile(path)
+	for _, module := range getModules(ctx, workPath, flags) {
 		results = append(results, &Result{
-			Path:   path,
-			Module: modFile.Module.Mod.Path,
+			Path:   module.Path,
+			Module: module.ModulePath,
+			Dir:    module.Path,
 		})
 	}
-	zaplog.SUG.Debugln(neatjsons.S(results))
+	flags.write(results)
 }
 
 // newVersionCmd creates version subcommand to show go versions
+// With --check it exits with status 1 when the modules use different go versions
+//
 // newVersionCmd 创建 version 子命令来显示 go 版本
-func newVersionCmd(workPath string) *cobra.Command {
-	return &cobra.Command{
+// 设置 --check 时，若各模块使用不同的 go 版本则以状态码 1 退出
+func newVersionCmd(workPath string, flags *rootFlags) *cobra.Command {
+	var check bool
+	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "List Go versions used in each module",
 		Long:  "Shows the Go version specified in each module's go.mod file",
 		Args:  cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
-			showVersionList(workPath)
+			modules := getModules(cmd.Context(), workPath, flags)
+			showVersionList(cmd.Context(), modules, flags)
+			if check {
+				checkVersions(modules)
+			}
 		},
 	}
+	cmd.Flags().BoolVar(&check, "check", false, "exit 1 when modules use different go versions")
+	cmd.AddCommand(newVersionSetCmd(workPath, flags))
+	return cmd
 }
 
 // showVersionList lists go versions from each module's go.mod
+// Besides the go directive, shows the toolchain and godebug directives, the highest go version required by the
+// dependencies found in the module cache, and whether the go directive exceeds the local Go
+//
 // showVersionList 列举每个模块 go.mod 中的 go 版本
-func showVersionList(workPath string) {
+// 除 go 指令外，还显示 toolchain 和 godebug 指令、模块缓存中依赖所要求的最高 go 版本，
+// 以及 go 指令是否超过本地 Go 版本
+func showVersionList(ctx context.Context, modules []workspath.Module, flags *rootFlags) {
 	type Result struct {
-		Path    string `json:"path"`
-		Module  string `json:"module"`
-		Version string `json:"version"`
+		Path        string   `json:"path"`
+		Module      string   `json:"module"`
+		Version     string   `json:"version"`
+		Toolchain   string   `json:"toolchain"`
+		Godebug     []string `json:"godebug"`
+		DepGo       string   `json:"depGo"`       // Highest go version among the dependencies // 依赖中最高的 go 版本
+		DepGoModule string   `json:"depGoModule"` // Dependency requiring DepGo // 要求 DepGo 的依赖
+		LocalGo     string   `json:"localGo"`     // Version of the local go command // 本地 go 命令的版本
+		TooNew      bool     `json:"tooNew"`      // Go directive exceeds the local Go // go 指令超过本地 Go 版本
+		Dir         string   `json:"-"`           // Same as Path, for templates like {{.Dir}} // 与 Path 相同，供 {{.Dir}} 这类模板使用
 	}
+	localGo := gover.Local(ctx)
+	depGo := newDepGoFinder(ctx)
 	var results []*Result
-	for _, path := range getModulePaths(workPath) {
-		modFile := parseModFile(path)
-		goVersion := tern.BFV(modFile.Go != nil, func() string {
-			return modFile.Go.Version
-		}, "unknown")
-		results = append(results, &Result{
-			Path:    path,
-			Module:  modFile.Module.Mod.Path,
-			Version: goVersion,
-		})
+	for _, module := range modules {
+		res := &Result{
+			Path:      module.Path,
+			Dir:       module.Path,
+			Module:    module.ModulePath,
+			Version:   tern.BVV(module.GoVersion != "", module.GoVersion, "unknown"),
+			Toolchain: module.Toolchain,
+			Godebug:   []string{},
+			LocalGo:   localGo,
+		}
+		for _, godebug := range module.Godebug {
+			res.Godebug = append(res.Godebug, godebug.Key+"="+godebug.Value)
+		}
+		res.DepGo, res.DepGoModule = depGo.highest(module)
+		res.TooNew = module.GoVersion != "" && gover.IsValid(localGo) && gover.Compare(module.GoVersion, localGo) > 0
+		results = append(results, res)
 	}
-	zaplog.SUG.Debugln(neatjsons.S(results))
+	// Misses are normal offline, so they are counted once here instead of logged per dependency
+	// 离线时缺失是正常的，因此在此统计一次，而不是每个依赖都输出日志
+	if depGo.missing > 0 {
+		zaplog.SUG.Infoln(depGo.missing, "dependencies not in the module cache, depGo is a lower bound")
+	}
+	flags.write(results)
 }
 
-// getModulePaths returns all Go module paths in workspace
-// getModulePaths 返回工作区中所有 Go 模块路径
-func getModulePaths(workPath string) []string {
-	return workspath.GetModulePaths(
-		workPath,
-		workspath.WithCurrentProject(),
-		workspath.ScanDeep(),
-		workspath.SkipNoGo(),
-	)
+// depGoFinder looks up the go directives of dependencies in the module cache, remembering each lookup
+// depGoFinder 在模块缓存中查找依赖的 go 指令，并记住每次查找的结果
+type depGoFinder struct {
+	cacheDIR string            // Module cache DIR // 模块缓存 DIR
+	versions map[string]string // Go version keyed by path@version // 以 path@version 为键的 go 版本
+	missing  int               // Count of path@version not found in the module cache // 模块缓存中未找到的 path@version 数量
+}
+
+func newDepGoFinder(ctx context.Context) *depGoFinder {
+	return &depGoFinder{cacheDIR: modcache.Dir(ctx), versions: map[string]string{}}
+}
+
+// highest returns the highest go version required by the dependencies of module, and the dependency requiring it
+// Dependencies missing from the module cache are skipped, so the result is a lower bound when offline
+//
+// highest 返回 module 的依赖所要求的最高 go 版本，以及要求该版本的依赖
+// 跳过模块缓存中没有的依赖，因此离线时结果为下界
+func (f *depGoFinder) highest(module workspath.Module) (string, string) {
+	var goVersion, depModule string
+	for _, require := range module.Require {
+		key := require.Path + "@" + require.Version
+		version, ok := f.versions[key]
+		if !ok {
+			var err error
+			version, err = modcache.GoVersion(f.cacheDIR, require.Path, require.Version)
+			if err != nil {
+				f.missing++
+			}
+			f.versions[key] = version
+		}
+		if version != "" && (goVersion == "" || gover.Compare(version, goVersion) > 0) {
+			goVersion, depModule = version, key
+		}
+	}
+	return goVersion, depModule
+}
+
+// getModules returns all Go modules in workspace, in topological order with --topo
+// Exits when the scan is interrupted or exceeds the timeout, warns about inaccessible paths and invalid go.mod files
+//
+// getModules 返回工作区中所有 Go 模块，设置 --topo 时按拓扑顺序排列
+// 扫描被中断或超时时退出，对无法访问的路径和无效的 go.mod 文件发出警告
+func getModules(ctx context.Context, workPath string, flags *rootFlags) []workspath.Module {
+	if flags.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, flags.timeout)
+		defer cancel()
+	}
+	modules, err := workspath.ScanModulesContext(ctx, workPath, append(flags.options(), workspath.ContinueOnError())...)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		fatal("scan stopped:", ctxErr)
+	}
+	var scanErrs workspath.ScanErrors
+	if errors.As(err, &scanErrs) {
+		for _, scanErr := range scanErrs {
+			zaplog.SUG.Warnln("skip:", scanErr.Error())
+		}
+	} else if err != nil {
+		fatal(err)
+	}
+	for _, module := range modules {
+		if module.Err != nil {
+			zaplog.SUG.Warnln("invalid go.mod:", module.Path, module.Err)
+		}
+	}
+	if flags.topo {
+		var ordered []workspath.Module
+		for _, level := range topoLevels(modules) {
+			ordered = append(ordered, level...)
+		}
+		return ordered
+	}
+	return modules
 }
 
-// parseModFile parses go.mod file and returns modfile.File
-// parseModFile 解析 go.mod 文件并返回 modfile.File
-func parseModFile(modulePath string) *modfile.File {
-	modPath := filepath.Join(modulePath, "go.mod")
-	content := rese.V1(os.ReadFile(modPath))
-	return rese.P1(modfile.Parse(modPath, content, nil))
+// fatal logs the message and exits with status 1
+// fatal 记录消息并以状态码 1 退出
+func fatal(args ...any) {
+	zaplog.SUG.Errorln(args...)
+	os.Exit(1)
 }
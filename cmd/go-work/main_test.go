@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-mate/go-work/internal/testfs"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+// TestMain runs main in place of the tests when runGoWork starts the test binary as go-work
+// TestMain 在 runGoWork 以 go-work 身份启动测试二进制时运行 main 而非测试
+func TestMain(m *testing.M) {
+	if os.Getenv("GO_WORK_RUN_MAIN") == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runGoWork runs go-work with args in dir and returns stdout, failing the test on a non-zero exit
+// runGoWork 在 dir 中以 args 运行 go-work 并返回 stdout，非零退出时使测试失败
+func runGoWork(t *testing.T, dir string, args ...string) []byte {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Dir = dir
+	// PWD keeps dir as given, so os.Getwd in go-work sees symlinked paths like a shell would
+	// PWD 保持 dir 的原样，使 go-work 中的 os.Getwd 与 shell 一样看到符号链接路径
+	cmd.Env = append(os.Environ(), "GO_WORK_RUN_MAIN=1", "PWD="+dir)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	require.NoError(t, cmd.Run(), stderr.String())
+	return stdout.Bytes()
+}
+
+// TestVersion_StdoutJSON tests stdout holds just the JSON results while logs go to stderr
+// A require missing from the module cache makes version log, like in offline use
+//
+// TestVersion_StdoutJSON 测试 stdout 只包含 JSON 结果，日志输出到 stderr
+// 模块缓存中缺少的 require 会使 version 输出日志，与离线使用时一样
+func TestVersion_StdoutJSON(t *testing.T) {
+	tempDIR := t.TempDir()
+	testfs.WriteGoMod(t, tempDIR, "example.com/app", "\nrequire example.invalid/missing v1.0.0\n")
+	testfs.WriteFile(t, filepath.Join(tempDIR, "main.go"), "package main\n")
+
+	var results []map[string]any
+	require.NoError(t, json.Unmarshal(runGoWork(t, tempDIR, "version"), &results))
+	require.Len(t, results, 1)
+	require.Equal(t, "example.com/app", results[0]["module"])
+}
+
+// TestList_ScanErrorWarns tests a path the scan cannot read is skipped with a warning instead of a panic
+// A .gitignore DIR stands in for an unreadable .gitignore, which root could still read
+//
+// TestList_ScanErrorWarns 测试扫描无法读取的路径会被跳过并警告，而不是 panic
+// 以 .gitignore DIR 代替不可读的 .gitignore，因为 root 仍能读取后者
+func TestList_ScanErrorWarns(t *testing.T) {
+	tempDIR := t.TempDir()
+	testfs.WriteGoMod(t, tempDIR, "ex.com/a", "")
+	testfs.WriteFile(t, filepath.Join(tempDIR, "a.go"), "package a\n")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "sub"), "ex.com/sub", "")
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDIR, "sub", ".gitignore"), 0755))
+
+	var results []map[string]any
+	require.NoError(t, json.Unmarshal(runGoWork(t, tempDIR, "--gitignore"), &results))
+	require.Len(t, results, 1)
+	require.Equal(t, "ex.com/a", results[0]["module"])
+}
+
+// TestList_TemplateDir tests per-module rows take {{.Dir}} as the module DIR
+// TestList_TemplateDir 测试每模块的行可以使用 {{.Dir}} 表示模块 DIR
+func TestList_TemplateDir(t *testing.T) {
+	tempDIR := rese.V1(filepath.EvalSymlinks(t.TempDir()))
+	testfs.WriteGoMod(t, tempDIR, "ex.com/a", "")
+	testfs.WriteFile(t, filepath.Join(tempDIR, "a.go"), "package a\n")
+
+	require.Equal(t, "ex.com/a\t"+tempDIR+"\n", string(runGoWork(t, tempDIR, "--template", "{{.Module}}\t{{.Dir}}")))
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/go-mate/go-work/workspath"
+	"github.com/spf13/cobra"
+	"github.com/yyle88/must"
+	"github.com/yyle88/zaplog"
+)
+
+// newPackagesCmd creates packages subcommand to list the packages of each module
+// newPackagesCmd 创建 packages 子命令，列出每个模块中的包
+func newPackagesCmd(workPath string, flags *rootFlags) *cobra.Command {
+	var mainOnly bool
+	cmd := &cobra.Command{
+		Use:   "packages",
+		Short: "List the packages of every discovered module",
+		Long:  "Parses package clauses and imports of the Go files in each module without invoking the go command, stopping at nested modules",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			showPackages(cmd, workPath, flags, mainOnly)
+		},
+	}
+	cmd.Flags().BoolVar(&mainOnly, "main", false, "list only main packages")
+	return cmd
+}
+
+// showPackages prints one row per package, unparsable files are skipped with a warning
+// showPackages 每个包输出一行，无法解析的文件跳过并输出警告
+func showPackages(cmd *cobra.Command, workPath string, flags *rootFlags, mainOnly bool) {
+	type Result struct {
+		Module     string `json:"module"`
+		ImportPath string `json:"importPath"`
+		Dir        string `json:"dir"`
+		Name       string `json:"name"`
+		Main       bool   `json:"main"`
+		TestOnly   bool   `json:"testOnly"`
+		Cgo        bool   `json:"cgo"`
+	}
+	results := []*Result{}
+	for _, module := range getModules(cmd.Context(), workPath, flags) {
+		if module.ModulePath == "" {
+			continue
+		}
+		packages, err := workspath.ScanPackagesContext(cmd.Context(), module, flags.packageOptions()...)
+		var scanErrs workspath.ScanErrors
+		if errors.As(err, &scanErrs) {
+			for _, scanErr := range scanErrs {
+				zaplog.SUG.Warnln("skip:", scanErr.Error())
+			}
+		} else {
+			must.Done(err)
+		}
+		for _, pkg := range packages {
+			if mainOnly && !pkg.IsMain {
+				continue
+			}
+			results = append(results, &Result{
+				Module:     module.ModulePath,
+				ImportPath: pkg.ImportPath,
+				Dir:        relName(workPath, pkg.Dir),
+				Name:       pkg.Name,
+				Main:       pkg.IsMain,
+				TestOnly:   pkg.TestOnly,
+				Cgo:        pkg.HasCgo,
+			})
+		}
+	}
+	flags.write(results)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"os"
+
+	"github.com/go-mate/go-work/internal/gitcmd"
+	"github.com/go-mate/go-work/internal/gittag"
+	"github.com/go-mate/go-work/internal/gover"
+	"github.com/go-mate/go-work/internal/output"
+	"github.com/go-mate/go-work/workrelease"
+	"github.com/spf13/cobra"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/zaplog"
+)
+
+// newReleaseCmd creates release subcommand grouping the release tools
+// newReleaseCmd 创建 release 子命令，汇集发布相关的工具
+func newReleaseCmd(workPath string, flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "release",
+		Short: "Check and tag modules for release",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newReleaseCheckCmd(workPath, flags))
+	cmd.AddCommand(newReleaseTagsCmd(workPath, flags))
+	cmd.AddCommand(newReleaseSIVCmd(workPath, flags))
+	return cmd
+}
+
+// newReleaseCheckCmd creates release check subcommand to verify every module is releasable
+// Exits with status 1 when any rule fails, so CI can block the tagging
+//
+// newReleaseCheckCmd 创建 release check 子命令，验证每个模块都可以发布
+// 任一规则失败时以状态码 1 退出，使 CI 可以阻止打标签
+func newReleaseCheckCmd(workPath string, flags *rootFlags) *cobra.Command {
+	var maxGo string
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Run release preflight rules over every module",
+		Long:  "Checks go.mod parsing, local replaces, pseudo-versions of tagged workspace modules, go.sum entries and the go directive, printing one row per module and rule",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if maxGo != "" && !gover.IsValid(maxGo) {
+				fatal("invalid go version:", maxGo)
+			}
+			opts := workrelease.CheckOptions{Root: workPath, MaxGo: maxGo}
+			if root, err := gitcmd.TopLevel(cmd.Context(), workPath); err != nil {
+				zaplog.SUG.Warnln("skip git tags:", err)
+			} else {
+				opts.Root = root
+				opts.Tags = rese.V1(gittag.Tags(cmd.Context(), root))
+			}
+			results := workrelease.Check(getModules(cmd.Context(), workPath, flags), opts)
+			if results == nil {
+				results = []*workrelease.Result{}
+			}
+			flags.write(results)
+			showReleaseSummary(workPath, results)
+			if workrelease.Failed(results) {
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&maxGo, "max-go", "", "highest go directive allowed by the policy, like 1.22")
+	return cmd
+}
+
+// showReleaseSummary prints one pass or fail row per module on stderr
+// showReleaseSummary 在 stderr 为每个模块输出一行 pass 或 fail
+func showReleaseSummary(workPath string, results []*workrelease.Result) {
+	type Result struct {
+		Module string             `json:"module"`
+		Path   string             `json:"path"`
+		Status workrelease.Status `json:"status"`
+		Failed []string           `json:"failed"`
+	}
+	var rows []*Result
+	index := map[string]*Result{}
+	for _, res := range results {
+		row, ok := index[res.Dir]
+		if !ok {
+			row = &Result{Module: res.Module, Path: relName(workPath, res.Dir), Status: workrelease.StatusPass, Failed: []string{}}
+			index[res.Dir] = row
+			rows = append(rows, row)
+		}
+		if res.Status == workrelease.StatusFail {
+			row.Status = workrelease.StatusFail
+			row.Failed = append(row.Failed, res.Rule)
+		}
+	}
+	must.Done(output.Write(os.Stderr, output.Table, rows))
+}
+
+// newReleaseTagsCmd creates release tags subcommand to propose the next tag of each module
+// Exits with status 1 when a module has major version issues
+//
+// newReleaseTagsCmd 创建 release tags 子命令，为每个模块建议下一个标签
+// 有模块存在主版本问题时以状态码 1 退出
+func newReleaseTagsCmd(workPath string, flags *rootFlags) *cobra.Command {
+	var bump string
+	cmd := &cobra.Command{
+		Use:   "tags",
+		Short: "Propose the next release tag of every module",
+		Long:  "Reads local git tags, prefixes nested modules with their DIR from the repo root and checks major versions against module paths",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			switch workrelease.Bump(bump) {
+			case workrelease.BumpPatch, workrelease.BumpMinor, workrelease.BumpMajor:
+			default:
+				fatal("invalid --bump, expect patch, minor or major:", bump)
+			}
+			root, err := gitcmd.TopLevel(cmd.Context(), workPath)
+			if err != nil {
+				fatal(err)
+			}
+			tags := rese.V1(gittag.Tags(cmd.Context(), root))
+			plans := workrelease.PlanTags(getModules(cmd.Context(), workPath, flags), root, tags, workrelease.Bump(bump))
+			if plans == nil {
+				plans = []*workrelease.TagPlan{}
+			}
+			flags.write(plans)
+			for _, plan := range plans {
+				if len(plan.Issues) > 0 {
+					os.Exit(1)
+				}
+			}
+		},
+	}
+	cmd.Flags().StringVar(&bump, "bump", string(workrelease.BumpPatch), "part of the latest tag to raise: patch, minor or major")
+	return cmd
+}
+
+// newReleaseSIVCmd creates release siv subcommand to check semantic import versioning of every module
+// Exits with status 1 when any issue is found
+//
+// newReleaseSIVCmd 创建 release siv 子命令，检查每个模块的语义导入版本
+// 发现任何问题时以状态码 1 退出
+func newReleaseSIVCmd(workPath string, flags *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "siv",
+		Short: "Check semantic import versioning of every module",
+		Long:  "Compares module path major suffixes with DIR layouts and git tags, and reports imports of workspace modules at a wrong major version",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			root, tags := workPath, []string(nil)
+			if top, err := gitcmd.TopLevel(cmd.Context(), workPath); err != nil {
+				zaplog.SUG.Warnln("skip git tags:", err)
+			} else {
+				root, tags = top, rese.V1(gittag.Tags(cmd.Context(), top))
+			}
+			results := workrelease.CheckSIV(getModules(cmd.Context(), workPath, flags), root, tags, flags.packageOptions()...)
+			if results == nil {
+				results = []*workrelease.SIVResult{}
+			}
+			flags.write(results)
+			for _, res := range results {
+				if len(res.Issues) > 0 {
+					os.Exit(1)
+				}
+			}
+		},
+	}
+}
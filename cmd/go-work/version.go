@@ -0,0 +1,145 @@
+package main
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-mate/go-work/internal/gover"
+	"github.com/go-mate/go-work/workspace"
+	"github.com/go-mate/go-work/workspath"
+	"github.com/spf13/cobra"
+	"github.com/yyle88/osexistpath/osomitexist"
+	"github.com/yyle88/tern"
+	"golang.org/x/mod/modfile"
+)
+
+// newVersionSetCmd creates version set subcommand to rewrite the go and toolchain directives
+// Touches every discovered go.mod, or those matching --match, and go.work when present
+//
+// newVersionSetCmd 创建 version set 子命令，改写 go 和 toolchain 指令
+// 修改每个发现的 go.mod（或匹配 --match 的那些），以及存在时的 go.work
+func newVersionSetCmd(workPath string, flags *rootFlags) *cobra.Command {
+	var toolchain string
+	var matches []string
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "set <go-version>",
+		Short: "Set the go version of each module",
+		Long:  "Rewrites the go directive, and the toolchain directive with --toolchain, in each go.mod and in go.work",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			goVersion := strings.TrimPrefix(args[0], "go")
+			if !modfile.GoVersionRE.MatchString(goVersion) {
+				fatal("invalid go version:", args[0])
+			}
+			if toolchain != "" && !modfile.ToolchainRE.MatchString(toolchain) {
+				fatal("invalid toolchain, expect a name like go1.23.4:", toolchain)
+			}
+			modules := getModules(cmd.Context(), workPath, flags)
+			changes := setGoVersions(workPath, modules, matches, goVersion, toolchain)
+			applyChanges(workPath, changes, dryRun)
+		},
+	}
+	cmd.Flags().StringVar(&toolchain, "toolchain", "", "also set the toolchain directive, like go1.23.4")
+	cmd.Flags().StringSliceVar(&matches, "match", nil, "touch only modules whose module path or relative DIR matches these globs")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print a unified diff instead of writing the files")
+	return cmd
+}
+
+// setGoVersions computes the go.mod and go.work changes that set goVersion and toolchain
+// The go line of go.work becomes the highest go version among the modules afterwards, so it never lags a member
+//
+// setGoVersions 计算设置 goVersion 和 toolchain 的 go.mod 与 go.work 变更
+// go.work 的 go 行取修改后各模块中的最高 go 版本，使其不会落后于任何成员
+func setGoVersions(workPath string, modules []workspath.Module, matches []string, goVersion string, toolchain string) []*workspath.Change {
+	var changes []*workspath.Change
+	goVersions := []string{goVersion}
+	for _, module := range modules {
+		if module.File == nil {
+			continue
+		}
+		if !matchModule(module, matches) {
+			goVersions = append(goVersions, module.GoVersion)
+			continue
+		}
+		change, err := workspath.EditGoMod(module.Path, func(modFile *modfile.File) error {
+			if err := modFile.AddGoStmt(goVersion); err != nil {
+				return err
+			}
+			if toolchain != "" {
+				return modFile.AddToolchainStmt(toolchain)
+			}
+			return nil
+		})
+		if err != nil {
+			fatal(err)
+		}
+		changes = append(changes, change)
+	}
+
+	if osomitexist.IsFile(filepath.Join(workPath, "go.work")) {
+		change, err := workspace.EditGoWork(workPath, func(workFile *modfile.WorkFile) error {
+			if err := workFile.AddGoStmt(gover.Max(goVersions...)); err != nil {
+				return err
+			}
+			if toolchain != "" {
+				return workFile.AddToolchainStmt(toolchain)
+			}
+			return nil
+		})
+		if err != nil {
+			fatal(err)
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// matchModule reports whether the module path or relative DIR of module matches any of patterns
+// No patterns matches every module
+//
+// matchModule 判断 module 的模块路径或相对 DIR 是否匹配任一 patterns
+// 没有 patterns 时匹配全部模块
+func matchModule(module workspath.Module, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, module.ModulePath); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, module.RelPath); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checkVersions exits with status 1 listing each go version when modules do not share one
+// checkVersions 在各模块的 go 版本不一致时列出每个版本并以状态码 1 退出
+func checkVersions(modules []workspath.Module) {
+	users := map[string][]string{}
+	for _, module := range modules {
+		if module.File != nil {
+			users[module.GoVersion] = append(users[module.GoVersion], module.ModulePath)
+		}
+	}
+	if len(users) <= 1 {
+		return
+	}
+	versions := make([]string, 0, len(users))
+	for version := range users {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return gover.Compare(versions[i], versions[j]) < 0
+	})
+	parts := make([]string, 0, len(versions))
+	for _, version := range versions {
+		label := tern.BVV(version != "", "go "+version, "no go directive")
+		parts = append(parts, label+" ("+strings.Join(users[version], ", ")+")")
+	}
+	fatal("go versions are not uniform:", strings.Join(parts, "; "))
+}
@@ -14,6 +14,7 @@ require (
 	github.com/yyle88/tern v0.0.10
 	github.com/yyle88/zaplog v0.0.28
 	golang.org/x/mod v0.23.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -30,5 +31,4 @@ require (
 	github.com/yyle88/syntaxgo v0.0.53 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 	go.uber.org/zap v1.27.1 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,38 @@
+// Package gitcmd: Runs the local git CLI for the packages reading git repos
+// Stderr of a failed command becomes part of the error, so callers can report it as is
+//
+// gitcmd: 为读取 git 仓库的包运行本地 git 命令行
+// 失败命令的 stderr 成为错误的一部分，调用方可以直接报告
+package gitcmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Run runs git with args in dir and returns stdout
+// Run 在 dir 中运行带 args 的 git 并返回 stdout
+func Run(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// TopLevel returns the absolute root DIR of the repo containing dir
+// TopLevel 返回 dir 所在仓库的绝对根 DIR
+func TopLevel(ctx context.Context, dir string) (string, error) {
+	out, err := Run(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
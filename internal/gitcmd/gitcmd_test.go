@@ -0,0 +1,29 @@
+package gitcmd
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-mate/go-work/internal/testfs"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+// TestTopLevel tests the repo root is found from a nested DIR and git errors carry stderr
+// TestTopLevel 测试从嵌套 DIR 找到仓库根，且 git 错误包含 stderr
+func TestTopLevel(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	ctx := context.Background()
+	repo := rese.V1(filepath.EvalSymlinks(t.TempDir()))
+	testfs.RunGit(t, repo, "init", "-q")
+	testfs.WriteFile(t, filepath.Join(repo, "a", "a.go"), "package a\n")
+
+	require.Equal(t, repo, rese.C1(TopLevel(ctx, filepath.Join(repo, "a"))))
+
+	_, err := Run(ctx, repo, "rev-parse", "--verify", "missing-ref")
+	require.ErrorContains(t, err, "git rev-parse --verify missing-ref")
+}
@@ -0,0 +1,90 @@
+// Package gitdiff: Lists files changed in a git repo through the local git CLI
+// Covers committed, staged and unstaged changes since a ref, plus untracked files
+//
+// gitdiff: 通过本地 git 命令行列出 git 仓库中变更的文件
+// 包含自某个 ref 以来已提交、已暂存和未暂存的变更，以及未跟踪的文件
+package gitdiff
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-mate/go-work/internal/gitcmd"
+)
+
+// ChangedFiles returns the absolute paths of files changed since ref in the repo containing dir
+// Compares ref with the working tree, so uncommitted changes count, and adds untracked files that are not ignored
+// Renames are listed as a deletion and an addition, so both the old and the new DIR count as changed
+//
+// ChangedFiles 返回 dir 所在仓库中自 ref 以来变更文件的绝对路径
+// 将 ref 与工作树比较，因此未提交的变更也计入，并加上未被忽略的未跟踪文件
+// 重命名按删除和新增列出，因此旧 DIR 和新 DIR 都计为变更
+func ChangedFiles(ctx context.Context, dir string, ref string) ([]string, error) {
+	topDIR, err := gitcmd.TopLevel(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	if topDIR, err = filepath.EvalSymlinks(topDIR); err != nil {
+		return nil, err
+	}
+
+	diff, err := gitcmd.Run(ctx, dir, "diff", "--name-only", "--no-relative", "--no-renames", "-z", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := gitcmd.Run(ctx, dir, "ls-files", "--others", "--exclude-standard", "-z", "--full-name", ":/")
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var files []string
+	for _, name := range append(splitZero(diff), splitZero(untracked)...) {
+		path := filepath.Join(topDIR, filepath.FromSlash(name))
+		if !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// ReadFiles reads one path per line from r, relative paths are resolved against base
+// Blank lines and lines starting with # are skipped
+//
+// ReadFiles 从 r 中每行读取一个路径，相对路径基于 base 解析
+// 跳过空行和以 # 开头的行
+func ReadFiles(r io.Reader, base string) ([]string, error) {
+	var files []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		path := filepath.FromSlash(line)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(base, path)
+		}
+		files = append(files, filepath.Clean(path))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// splitZero splits NUL separated output, dropping the blank tail
+// splitZero 拆分以 NUL 分隔的输出，去掉末尾的空项
+func splitZero(data []byte) []string {
+	var names []string
+	for _, name := range strings.Split(string(data), "\x00") {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
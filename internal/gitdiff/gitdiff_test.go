@@ -0,0 +1,60 @@
+package gitdiff
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-mate/go-work/internal/testfs"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+// TestChangedFiles tests committed, modified, deleted, moved and untracked files are listed
+// TestChangedFiles 测试已提交、已修改、已删除、已移动和未跟踪的文件都会被列出
+func TestChangedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	repo := rese.V1(filepath.EvalSymlinks(t.TempDir()))
+	testfs.RunGit(t, repo, "init", "-q")
+	testfs.WriteFile(t, filepath.Join(repo, "a", "a.go"), "package a\n")
+	testfs.WriteFile(t, filepath.Join(repo, "b", "b.go"), "package b\n")
+	testfs.WriteFile(t, filepath.Join(repo, "c", "c.go"), "package c\n")
+	testfs.WriteFile(t, filepath.Join(repo, "e", "moved.go"), "package e\n\n// Moved keeps its content so git sees a rename\nvar Moved = 1\n")
+	testfs.WriteFile(t, filepath.Join(repo, ".gitignore"), "*.log\n")
+	testfs.RunGit(t, repo, "add", "-A")
+	testfs.RunGit(t, repo, "commit", "-q", "-m", "base")
+	testfs.RunGit(t, repo, "tag", "base")
+
+	testfs.WriteFile(t, filepath.Join(repo, "a", "a.go"), "package a\n\nvar A = 1\n")
+	testfs.RunGit(t, repo, "commit", "-q", "-am", "change a")
+	testfs.WriteFile(t, filepath.Join(repo, "b", "b.go"), "package b\n\nvar B = 1\n")
+	require.NoError(t, os.Remove(filepath.Join(repo, "c", "c.go")))
+	testfs.WriteFile(t, filepath.Join(repo, "d", "d.go"), "package d\n")
+	testfs.WriteFile(t, filepath.Join(repo, "d", "debug.log"), "ignored\n")
+	testfs.RunGit(t, repo, "mv", filepath.Join("e", "moved.go"), filepath.Join("a", "moved.go"))
+
+	files := rese.V1(ChangedFiles(context.Background(), filepath.Join(repo, "a"), "base"))
+	require.Equal(t, []string{
+		filepath.Join(repo, "a", "a.go"),
+		filepath.Join(repo, "a", "moved.go"),
+		filepath.Join(repo, "b", "b.go"),
+		filepath.Join(repo, "c", "c.go"),
+		filepath.Join(repo, "e", "moved.go"),
+		filepath.Join(repo, "d", "d.go"),
+	}, files)
+
+	_, err := ChangedFiles(context.Background(), repo, "no-such-ref")
+	require.ErrorContains(t, err, "git diff")
+}
+
+// TestReadFiles tests relative paths are resolved and comments skipped
+// TestReadFiles 测试相对路径会被解析且注释被跳过
+func TestReadFiles(t *testing.T) {
+	files := rese.V1(ReadFiles(strings.NewReader("a/a.go\n\n# note\n/abs/b.go\n./c/../d.go\n"), "/w"))
+	require.Equal(t, []string{"/w/a/a.go", "/abs/b.go", "/w/d.go"}, files)
+}
@@ -0,0 +1,29 @@
+// Package gittag: Reads the tags of a git repo through the local git CLI
+// Works on local refs only, tags on the remote are not fetched
+//
+// gittag: 通过本地 git 命令行读取 git 仓库的标签
+// 只读取本地引用，不拉取远程的标签
+package gittag
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-mate/go-work/internal/gitcmd"
+)
+
+// Tags returns the names of the tags in the repo containing dir, sorted by name, empty but not nil without tags
+// Tags 返回 dir 所在仓库中的标签名称，按名称排序，没有标签时返回空而非 nil 的切片
+func Tags(ctx context.Context, dir string) ([]string, error) {
+	out, err := gitcmd.Run(ctx, dir, "tag", "--list")
+	if err != nil {
+		return nil, err
+	}
+	tags := []string{}
+	for _, line := range strings.Split(string(out), "\n") {
+		if tag := strings.TrimSpace(line); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
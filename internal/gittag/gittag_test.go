@@ -0,0 +1,32 @@
+package gittag
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-mate/go-work/internal/testfs"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+// TestTags tests the tags are read from a nested DIR and a DIR outside a repo fails
+// TestTags 测试从嵌套 DIR 读取标签，且仓库外的 DIR 会失败
+func TestTags(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	ctx := context.Background()
+	repo := rese.V1(filepath.EvalSymlinks(t.TempDir()))
+	testfs.RunGit(t, repo, "init", "-q")
+	testfs.RunGit(t, repo, "commit", "-q", "--allow-empty", "-m", "init")
+	testfs.RunGit(t, repo, "tag", "v1.0.0")
+	testfs.RunGit(t, repo, "tag", "libs/core/v0.2.0")
+	testfs.WriteFile(t, filepath.Join(repo, "libs", "core", "core.go"), "package core\n")
+
+	require.Equal(t, []string{"libs/core/v0.2.0", "v1.0.0"}, rese.V1(Tags(ctx, filepath.Join(repo, "libs", "core"))))
+
+	_, err := Tags(ctx, t.TempDir())
+	require.Error(t, err)
+}
@@ -0,0 +1,140 @@
+// Package gover: Go version comparison for go and toolchain directives
+// Follows the ordering of the go command: 1.21 < 1.21rc1 < 1.21.0 < 1.21.1
+//
+// gover: 用于 go 和 toolchain 指令的 Go 版本比较
+// 遵循 go 命令的排序规则：1.21 < 1.21rc1 < 1.21.0 < 1.21.1
+package gover
+
+import (
+	"context"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// version holds the parsed parts of a Go version
+// version 保存 Go 版本解析后的各部分
+type version struct {
+	major string // Major number // 主版本号
+	minor string // Minor number // 次版本号
+	patch string // Patch number, blank in language versions // 补丁号，语言版本中为空
+	kind  string // "", "alpha", "beta" or "rc" // ""、"alpha"、"beta" 或 "rc"
+	pre   string // Pre-release number // 预发布序号
+}
+
+// versionRE matches Go versions, with optional "go" prefix and pre-release suffix
+// versionRE 匹配 Go 版本，可带 "go" 前缀和预发布后缀
+var versionRE = regexp.MustCompile(`^(?:go)?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:(alpha|beta|rc)(\d+))?$`)
+
+// parse splits x into its parts, ok is false when x is not a Go version
+// parse 将 x 拆分为各部分，x 不是 Go 版本时 ok 为 false
+func parse(x string) (version, bool) {
+	match := versionRE.FindStringSubmatch(x)
+	if match == nil {
+		return version{}, false
+	}
+	v := version{major: match[1], minor: match[2], patch: match[3], kind: match[4], pre: match[5]}
+	if v.minor == "" {
+		v.minor = "0"
+	}
+	// Go 1.21 and later name releases with patch numbers, 1.21 is the language version before 1.21rc1
+	// Go 1.21 及之后的发布带补丁号，1.21 是 1.21rc1 之前的语言版本
+	if v.patch == "" && v.kind == "" && (v.major != "1" || cmpInt(v.minor, "21") < 0) {
+		v.patch = "0"
+	}
+	return v, true
+}
+
+// IsValid reports whether x is a Go version, like "1.22", "1.22.8", "1.23rc1" or "go1.22.8"
+// IsValid 判断 x 是否为 Go 版本，如 "1.22"、"1.22.8"、"1.23rc1" 或 "go1.22.8"
+func IsValid(x string) bool {
+	_, ok := parse(x)
+	return ok
+}
+
+// Compare returns -1, 0 or +1 as x is lower, equal or higher than y
+// Invalid versions sort before valid ones
+//
+// Compare 在 x 低于、等于或高于 y 时分别返回 -1、0 或 +1
+// 无效版本排在有效版本之前
+func Compare(x string, y string) int {
+	vx, okx := parse(x)
+	vy, oky := parse(y)
+	switch {
+	case !okx && !oky:
+		return strings.Compare(x, y)
+	case !okx:
+		return -1
+	case !oky:
+		return +1
+	}
+	if c := cmpInt(vx.major, vy.major); c != 0 {
+		return c
+	}
+	if c := cmpInt(vx.minor, vy.minor); c != 0 {
+		return c
+	}
+	if c := cmpInt(vx.patch, vy.patch); c != 0 {
+		return c
+	}
+	// Pre-releases have blank patch, so "" < "alpha" < "beta" < "rc" just orders them
+	// 预发布版本的补丁号为空，因此 "" < "alpha" < "beta" < "rc" 即可排序
+	if c := strings.Compare(vx.kind, vy.kind); c != 0 {
+		return c
+	}
+	return cmpInt(vx.pre, vy.pre)
+}
+
+// Max returns the highest of the versions, blank when none is given
+// Max 返回各版本中最高的一个，未给出版本时返回空
+func Max(versions ...string) string {
+	res := ""
+	for _, v := range versions {
+		if v != "" && (res == "" || Compare(v, res) > 0) {
+			res = v
+		}
+	}
+	return res
+}
+
+// Local returns the Go version the go command in PATH runs, like "go1.23.4"
+// Asks go env GOVERSION so GOTOOLCHAIN switches are counted, falls back to the version of this binary
+//
+// Local 返回 PATH 中的 go 命令运行的 Go 版本，如 "go1.23.4"
+// 通过 go env GOVERSION 获取以计入 GOTOOLCHAIN 切换，失败时回退到当前程序的版本
+func Local(ctx context.Context) string {
+	if out, err := exec.CommandContext(ctx, "go", "env", "GOVERSION").Output(); err == nil {
+		if v := strings.TrimSpace(string(out)); IsValid(v) {
+			return v
+		}
+	}
+	return runtime.Version()
+}
+
+// Lang returns the language version of x, like "1.22" from "1.22.8" or "go1.22rc1"
+// Lang 返回 x 的语言版本，如从 "1.22.8" 或 "go1.22rc1" 得到 "1.22"
+func Lang(x string) string {
+	v, ok := parse(x)
+	if !ok {
+		return ""
+	}
+	return v.major + "." + v.minor
+}
+
+// cmpInt compares decimal strings by value, blank being the lowest
+// cmpInt 按数值比较十进制字符串，空字符串最小
+func cmpInt(x string, y string) int {
+	if x == "" || y == "" {
+		return strings.Compare(x, y)
+	}
+	x = strings.TrimLeft(x, "0")
+	y = strings.TrimLeft(y, "0")
+	if len(x) != len(y) {
+		if len(x) < len(y) {
+			return -1
+		}
+		return +1
+	}
+	return strings.Compare(x, y)
+}
@@ -0,0 +1,53 @@
+package gover
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompare tests Go version ordering
+// TestCompare 测试 Go 版本排序
+func TestCompare(t *testing.T) {
+	ordered := []string{"1.19rc1", "1.19", "1.19.1", "1.21", "1.21rc1", "1.21.0", "1.21.10", "1.22", "go1.22.8", "1.23beta1", "1.23rc2", "1.23.0", "2"}
+	for i := range ordered {
+		for j := range ordered {
+			expected := 0
+			switch {
+			case i < j:
+				expected = -1
+			case i > j:
+				expected = +1
+			}
+			require.Equal(t, expected, Compare(ordered[i], ordered[j]), "%s vs %s", ordered[i], ordered[j])
+		}
+	}
+	require.Equal(t, 0, Compare("1.20", "1.20.0"))
+	require.Equal(t, -1, Compare("bad", "1.0"))
+}
+
+// TestMax tests picking the highest version
+// TestMax 测试选出最高版本
+func TestMax(t *testing.T) {
+	require.Equal(t, "1.22.8", Max("1.21", "", "1.22.8", "1.22"))
+	require.Equal(t, "", Max())
+}
+
+// TestLang tests language version extraction
+// TestLang 测试语言版本提取
+func TestLang(t *testing.T) {
+	require.Equal(t, "1.22", Lang("1.22.8"))
+	require.Equal(t, "1.23", Lang("go1.23rc1"))
+	require.True(t, IsValid("1.22"))
+	require.False(t, IsValid("v1.22"))
+	require.Equal(t, "", Lang("bad"))
+}
+
+// TestLocal tests the local Go version is a valid version
+// TestLocal 测试本地 Go 版本是有效的版本
+func TestLocal(t *testing.T) {
+	local := Local(context.Background())
+	t.Log(local)
+	require.True(t, IsValid(local))
+}
@@ -0,0 +1,67 @@
+// Package modcache: Reads go.mod files of dependencies from the local module cache
+// Works offline, dependencies missing from the cache are reported as not found
+//
+// modcache: 从本地模块缓存读取依赖的 go.mod 文件
+// 离线运行，缓存中没有的依赖报告为未找到
+package modcache
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// Dir returns the module cache DIR, asking go env first and then falling back to GOPATH/pkg/mod
+// Dir 返回模块缓存 DIR，先询问 go env，再回退到 GOPATH/pkg/mod
+func Dir(ctx context.Context) string {
+	if out, err := exec.CommandContext(ctx, "go", "env", "GOMODCACHE").Output(); err == nil {
+		if dir := strings.TrimSpace(string(out)); dir != "" {
+			return dir
+		}
+	}
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	if paths := filepath.SplitList(os.Getenv("GOPATH")); len(paths) > 0 && paths[0] != "" {
+		return filepath.Join(paths[0], "pkg", "mod")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, "go", "pkg", "mod")
+}
+
+// GoVersion returns the go directive of the go.mod of path at version, blank when it has none
+// The error wraps os.ErrNotExist when the go.mod is not in the cache under dir
+//
+// GoVersion 返回 path 在 version 版本时 go.mod 的 go 指令，没有该指令时返回空
+// go.mod 不在 dir 下的缓存中时，错误包装 os.ErrNotExist
+func GoVersion(dir string, path string, version string) (string, error) {
+	escPath, err := module.EscapePath(path)
+	if err != nil {
+		return "", err
+	}
+	escVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return "", err
+	}
+	modPath := filepath.Join(dir, "cache", "download", filepath.FromSlash(escPath), "@v", escVersion+".mod")
+	content, err := os.ReadFile(modPath)
+	if err != nil {
+		return "", err
+	}
+	modFile, err := modfile.ParseLax(modPath, content, nil)
+	if err != nil {
+		return "", err
+	}
+	if modFile.Go == nil {
+		return "", nil
+	}
+	return modFile.Go.Version, nil
+}
@@ -0,0 +1,36 @@
+package modcache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+// TestGoVersion tests go.mod files are found by escaped path and version
+// TestGoVersion 测试按转义后的路径和版本找到 go.mod 文件
+func TestGoVersion(t *testing.T) {
+	dir := t.TempDir()
+	modDIR := filepath.Join(dir, "cache", "download", "github.com", "!burnt!sushi", "toml", "@v")
+	require.NoError(t, os.MkdirAll(modDIR, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(modDIR, "v1.4.0.mod"), []byte("module github.com/BurntSushi/toml\n\ngo 1.18\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(modDIR, "v0.3.1.mod"), []byte("module github.com/BurntSushi/toml\n"), 0644))
+
+	require.Equal(t, "1.18", rese.C1(GoVersion(dir, "github.com/BurntSushi/toml", "v1.4.0")))
+	require.Equal(t, "", rese.V1(GoVersion(dir, "github.com/BurntSushi/toml", "v0.3.1")))
+
+	_, err := GoVersion(dir, "github.com/BurntSushi/toml", "v9.9.9")
+	require.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+// TestDir tests the module cache DIR is found
+// TestDir 测试能够找到模块缓存 DIR
+func TestDir(t *testing.T) {
+	dir := Dir(context.Background())
+	require.NotEmpty(t, dir)
+	t.Log(dir)
+}
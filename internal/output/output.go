@@ -0,0 +1,264 @@
+// Package output: Renders command results to stdout in selectable formats
+// Keeps data on stdout clean of log prefixes so it can be piped into jq and friends
+//
+// output: 以可选格式将命令结果输出到 stdout
+// 保持 stdout 上的数据不含日志前缀，以便通过管道交给 jq 等工具处理
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format names a rendering of result rows
+// Format 表示结果行的一种呈现方式
+type Format string
+
+const (
+	JSON   Format = "json"   // Indented JSON array // 缩进的 JSON 数组
+	NDJSON Format = "ndjson" // One JSON object per line // 每行一个 JSON 对象
+	YAML   Format = "yaml"   // YAML sequence // YAML 序列
+	CSV    Format = "csv"    // Comma separated values with header // 带表头的逗号分隔值
+	TSV    Format = "tsv"    // Tab separated values with header // 带表头的制表符分隔值
+	Table  Format = "table"  // Aligned columns with header // 带表头的对齐列
+)
+
+// Formats lists every supported format
+// Formats 列出全部支持的格式
+func Formats() []Format {
+	return []Format{JSON, NDJSON, YAML, CSV, TSV, Table}
+}
+
+// Parse checks name against the supported formats
+// Parse 检查 name 是否为支持的格式
+func Parse(name string) (Format, error) {
+	for _, format := range Formats() {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q, expect one of %v", name, Formats())
+}
+
+// Write renders rows into w using format
+// Rows is a slice of structs or struct pointers, columns follow the json tags of the fields
+// A non-slice value is rendered as a single row
+//
+// Write 使用 format 将 rows 呈现到 w
+// rows 是结构体或结构体指针的切片，列名遵循字段的 json 标签
+// 非切片值按单行呈现
+func Write(w io.Writer, format Format, rows any) error {
+	items := toItems(rows)
+	switch format {
+	case JSON:
+		return writeJSON(w, items)
+	case NDJSON:
+		return writeNDJSON(w, items)
+	case YAML:
+		return writeYAML(w, items)
+	case CSV:
+		return writeCSV(w, ',', items)
+	case TSV:
+		return writeCSV(w, '\t', items)
+	case Table:
+		return writeTable(w, items)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// toItems converts rows into a slice of values, nil rows becoming blank
+// toItems 将 rows 转换为值切片，nil 的 rows 视为空
+func toItems(rows any) []any {
+	value := reflect.ValueOf(rows)
+	if !value.IsValid() || (value.Kind() == reflect.Slice && value.IsNil()) {
+		return []any{}
+	}
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return []any{rows}
+	}
+	items := make([]any, 0, value.Len())
+	for idx := 0; idx < value.Len(); idx++ {
+		items = append(items, value.Index(idx).Interface())
+	}
+	return items
+}
+
+func writeJSON(w io.Writer, items []any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(items)
+}
+
+func writeNDJSON(w io.Writer, items []any) error {
+	encoder := json.NewEncoder(w)
+	for _, item := range items {
+		if err := encoder.Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeYAML renders through JSON, so field names and order match the JSON output
+// writeYAML 经由 JSON 呈现，使字段名和顺序与 JSON 输出一致
+func writeYAML(w io.Writer, items []any) error {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	resetStyle(&node)
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// resetStyle drops the JSON flow and quoting styles so the node renders as block YAML
+// resetStyle 去除 JSON 的流式和引号样式，使节点以块状 YAML 呈现
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+func writeCSV(w io.Writer, comma rune, items []any) error {
+	columns, records := tabulate(items)
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+func writeTable(w io.Writer, items []any) error {
+	columns, records := tabulate(items)
+	writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	headers := make([]string, 0, len(columns))
+	for _, column := range columns {
+		headers = append(headers, strings.ToUpper(column))
+	}
+	fmt.Fprintln(writer, strings.Join(headers, "\t"))
+	for _, record := range records {
+		fmt.Fprintln(writer, strings.Join(record, "\t"))
+	}
+	return writer.Flush()
+}
+
+// tabulate returns the column names and the cell text of each row
+// tabulate 返回列名和每行的单元格文本
+func tabulate(items []any) ([]string, [][]string) {
+	var fields []reflect.StructField
+	var columns []string
+	records := make([][]string, 0, len(items))
+	for _, item := range items {
+		value := reflect.Indirect(reflect.ValueOf(item))
+		if value.Kind() != reflect.Struct {
+			if columns == nil {
+				columns = []string{"value"}
+			}
+			records = append(records, []string{formatCell(value)})
+			continue
+		}
+		if fields == nil {
+			fields, columns = structColumns(value.Type())
+		}
+		record := make([]string, 0, len(fields))
+		for _, field := range fields {
+			record = append(record, formatCell(value.FieldByIndex(field.Index)))
+		}
+		records = append(records, record)
+	}
+	return columns, records
+}
+
+// structColumns returns the exported fields of typ and their json names, skipping `json:"-"`
+// structColumns 返回 typ 的导出字段及其 json 名称，跳过 `json:"-"`
+func structColumns(typ reflect.Type) ([]reflect.StructField, []string) {
+	var fields []reflect.StructField
+	var columns []string
+	for idx := 0; idx < typ.NumField(); idx++ {
+		field := typ.Field(idx)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, field)
+		columns = append(columns, name)
+	}
+	return fields, columns
+}
+
+// formatCell renders scalars as text, scalar slices joined with commas and the rest as JSON
+// formatCell 将标量呈现为文本，标量切片以逗号拼接，其余呈现为 JSON
+func formatCell(value reflect.Value) string {
+	if !value.IsValid() {
+		return ""
+	}
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return ""
+		}
+		return formatCell(value.Elem())
+	case reflect.String:
+		return value.String()
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(value.Interface())
+	case reflect.Slice, reflect.Array:
+		if isScalar(value.Type().Elem()) {
+			parts := make([]string, 0, value.Len())
+			for idx := 0; idx < value.Len(); idx++ {
+				parts = append(parts, formatCell(value.Index(idx)))
+			}
+			return strings.Join(parts, ",")
+		}
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value.Interface()); err != nil {
+		return fmt.Sprint(value.Interface())
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// isScalar reports whether typ renders as plain text
+// isScalar 判断 typ 是否以纯文本呈现
+func isScalar(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,99 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testRow struct {
+	Path    string   `json:"path"`
+	Module  string   `json:"module"`
+	Version string   `json:"version,omitempty"`
+	Tags    []string `json:"tags"`
+	Hidden  string   `json:"-"`
+}
+
+var testRows = []*testRow{
+	{Path: "/w/api", Module: "example.com/api", Version: "1.22", Tags: []string{"a", "b"}, Hidden: "x"},
+	{Path: "/w/lib", Module: "example.com/lib", Tags: nil},
+}
+
+// render writes testRows with format and returns the text
+// render 使用 format 输出 testRows 并返回文本
+func render(t *testing.T, format Format, rows any) string {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, format, rows))
+	return buf.String()
+}
+
+// TestWrite_JSON tests JSON and NDJSON output
+// TestWrite_JSON 测试 JSON 和 NDJSON 输出
+func TestWrite_JSON(t *testing.T) {
+	require.Equal(t, `[
+  {
+    "path": "/w/api",
+    "module": "example.com/api",
+    "version": "1.22",
+    "tags": [
+      "a",
+      "b"
+    ]
+  },
+  {
+    "path": "/w/lib",
+    "module": "example.com/lib",
+    "tags": null
+  }
+]
+`, render(t, JSON, testRows))
+
+	require.Equal(t, `{"path":"/w/api","module":"example.com/api","version":"1.22","tags":["a","b"]}
+{"path":"/w/lib","module":"example.com/lib","tags":null}
+`, render(t, NDJSON, testRows))
+
+	require.Equal(t, "[]\n", render(t, JSON, []*testRow(nil)))
+}
+
+// TestWrite_YAML tests YAML output keeps the JSON field order
+// TestWrite_YAML 测试 YAML 输出保持 JSON 字段顺序
+func TestWrite_YAML(t *testing.T) {
+	require.Equal(t, `- path: /w/api
+  module: example.com/api
+  version: "1.22"
+  tags:
+    - a
+    - b
+- path: /w/lib
+  module: example.com/lib
+  tags: null
+`, render(t, YAML, testRows))
+}
+
+// TestWrite_Tabular tests CSV, TSV and table output
+// TestWrite_Tabular 测试 CSV、TSV 和表格输出
+func TestWrite_Tabular(t *testing.T) {
+	require.Equal(t, `path,module,version,tags
+/w/api,example.com/api,1.22,"a,b"
+/w/lib,example.com/lib,,
+`, render(t, CSV, testRows))
+
+	require.Equal(t, "path\tmodule\tversion\ttags\n/w/api\texample.com/api\t1.22\ta,b\n/w/lib\texample.com/lib\t\t\n", render(t, TSV, testRows))
+
+	require.Equal(t, `PATH    MODULE           VERSION  TAGS
+/w/api  example.com/api  1.22     a,b
+/w/lib  example.com/lib           
+`, render(t, Table, testRows))
+}
+
+// TestParse tests format name validation
+// TestParse 测试格式名称校验
+func TestParse(t *testing.T) {
+	format, err := Parse("table")
+	require.NoError(t, err)
+	require.Equal(t, Table, format)
+
+	_, err = Parse("xml")
+	require.Error(t, err)
+}
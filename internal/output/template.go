@@ -0,0 +1,62 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// TemplateFuncs returns the helper functions available in templates
+// Paths given to rel are made relative to base, like go list -f the join helper takes elems then sep
+//
+// TemplateFuncs 返回模板中可用的辅助函数
+// rel 将路径转换为相对 base 的路径，与 go list -f 一致 join 先接收 elems 再接收 sep
+func TemplateFuncs(base string) template.FuncMap {
+	return template.FuncMap{
+		"rel": func(path string) string {
+			if rel, err := filepath.Rel(base, path); err == nil {
+				return filepath.ToSlash(rel)
+			}
+			return path
+		},
+		"join": strings.Join,
+		"json": func(value any) (string, error) {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return "", err
+			}
+			return string(data), nil
+		},
+	}
+}
+
+// ParseTemplate parses text as a row template with TemplateFuncs of base
+// ParseTemplate 使用 base 的 TemplateFuncs 将 text 解析为行模板
+func ParseTemplate(base string, text string) (*template.Template, error) {
+	return template.New("row").Funcs(TemplateFuncs(base)).Parse(text)
+}
+
+// WriteTemplate executes tmpl against each row, ending each with a newline
+// Rows are passed as the struct values, so fields are referenced by Go names like {{.Module}}
+// Each row is rendered into a buffer first, so a failing row leaves no partial line behind
+//
+// WriteTemplate 对每一行执行 tmpl，每行以换行结束
+// 行以结构体值传入，因此字段以 Go 名称引用，例如 {{.Module}}
+// 每行先渲染到缓冲区，因此失败的行不会留下不完整的内容
+func WriteTemplate(w io.Writer, tmpl *template.Template, rows any) error {
+	var line bytes.Buffer
+	for _, item := range toItems(rows) {
+		line.Reset()
+		if err := tmpl.Execute(&line, item); err != nil {
+			return err
+		}
+		line.WriteByte('\n')
+		if _, err := w.Write(line.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+// TestWriteTemplate tests each row renders on its own line with helper functions
+// TestWriteTemplate 测试每行单独输出并可使用辅助函数
+func TestWriteTemplate(t *testing.T) {
+	tmpl := rese.P1(ParseTemplate("/w", `{{.Module}}	{{rel .Path}}	{{join .Tags ","}}	{{json .Version}}`))
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteTemplate(&buf, tmpl, testRows))
+	require.Equal(t, "example.com/api\tapi\ta,b\t\"1.22\"\nexample.com/lib\tlib\t\t\"\"\n", buf.String())
+}
+
+// TestParseTemplate tests syntax and field errors are reported without partial output
+// TestParseTemplate 测试语法错误和字段错误会被报告，且没有不完整的输出
+func TestParseTemplate(t *testing.T) {
+	_, err := ParseTemplate("/w", "{{.Module")
+	require.Error(t, err)
+
+	// A field error leaves no partial line behind
+	// 字段错误不会留下不完整的行
+	tmpl := rese.P1(ParseTemplate("/w", "{{.Module}}\t{{.Missing}}"))
+	var buf bytes.Buffer
+	require.Error(t, WriteTemplate(&buf, tmpl, testRows))
+	require.Empty(t, buf.String())
+}
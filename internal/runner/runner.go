@@ -0,0 +1,179 @@
+// Package runner: Runs one command in many module DIRs
+// Bounds parallelism, labels output per module and collects each exit status
+//
+// runner: 在多个模块 DIR 中运行同一命令
+// 限制并行数，按模块标注输出并收集每个退出状态
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// Task is one DIR to run the command in
+// Task 是运行命令的一个 DIR
+type Task struct {
+	Dir   string // Working DIR of the command // 命令的工作 DIR
+	Label string // Name shown in output and summary // 在输出和汇总中显示的名称
+}
+
+// Options tunes how the tasks run
+// Options 调整任务的运行方式
+type Options struct {
+	Parallel  int       // Tasks running at once, at least 1 // 同时运行的任务数，至少为 1
+	KeepGoing bool      // Start remaining tasks after a failure // 失败后继续启动剩余任务
+	Grouped   bool      // Print each task's output in one piece when it ends // 任务结束时整体输出其内容
+	Stdout    io.Writer // Receives the command stdout // 接收命令的 stdout
+	Stderr    io.Writer // Receives the command stderr // 接收命令的 stderr
+}
+
+// Status is the outcome of a task
+// Status 是任务的结果
+type Status string
+
+const (
+	StatusOK      Status = "ok"      // Command exited with 0 // 命令以 0 退出
+	StatusFailed  Status = "failed"  // Command failed or could not start // 命令失败或无法启动
+	StatusSkipped Status = "skipped" // Not started after an earlier failure // 因之前的失败而未启动
+)
+
+// Result is the outcome of one task
+// Result 是单个任务的结果
+type Result struct {
+	Task     Task
+	Status   Status
+	ExitCode int           // Exit code, -1 when the command did not exit normally // 退出码，命令未正常退出时为 -1
+	Duration time.Duration // Wall time of the command // 命令的运行时长
+	Err      error         // Start or wait error, nil on success // 启动或等待错误，成功时为 nil
+}
+
+// Run runs name with args in the DIR of each task
+// Results keep the task order, tasks not started after a failure are skipped unless KeepGoing is set
+// Output lines are prefixed with "[label] ", or printed per task when Grouped is set
+//
+// Run 在每个任务的 DIR 中运行带 args 的 name
+// 结果保持任务顺序，未设置 KeepGoing 时失败后未启动的任务被跳过
+// 输出行以 "[label] " 为前缀，设置 Grouped 时按任务整体输出
+func Run(ctx context.Context, tasks []Task, name string, args []string, opts Options) []*Result {
+	results := make([]*Result, len(tasks))
+	for idx, task := range tasks {
+		results[idx] = &Result{Task: task, Status: StatusSkipped}
+	}
+
+	var mutex sync.Mutex
+	var failed bool
+	slots := make(chan struct{}, max(opts.Parallel, 1))
+	var wg sync.WaitGroup
+	for _, res := range results {
+		slots <- struct{}{}
+		mutex.Lock()
+		stop := failed && !opts.KeepGoing
+		mutex.Unlock()
+		if stop || ctx.Err() != nil {
+			<-slots
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			runTask(ctx, res, name, args, opts, &mutex)
+			if res.Status == StatusFailed {
+				mutex.Lock()
+				failed = true
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// runTask runs the command of res, output writes are serialized through mutex
+// runTask 运行 res 的命令，输出写入通过 mutex 串行化
+func runTask(ctx context.Context, res *Result, name string, args []string, opts Options, mutex *sync.Mutex) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = res.Task.Dir
+
+	var stdout, stderr io.Writer
+	var buf *bytes.Buffer
+	if opts.Grouped {
+		buf = &bytes.Buffer{}
+		stdout, stderr = buf, buf
+	} else {
+		prefix := "[" + res.Task.Label + "] "
+		outLines := &lineWriter{w: opts.Stdout, prefix: prefix, mutex: mutex}
+		errLines := &lineWriter{w: opts.Stderr, prefix: prefix, mutex: mutex}
+		defer outLines.Flush()
+		defer errLines.Flush()
+		stdout, stderr = outLines, errLines
+	}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	res.Duration = time.Since(start)
+	res.ExitCode = 0
+	res.Status = StatusOK
+	if err != nil {
+		res.Err = err
+		res.Status = StatusFailed
+		res.ExitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			res.ExitCode = exitErr.ExitCode()
+		}
+	}
+
+	if buf != nil {
+		if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		_, _ = io.WriteString(opts.Stdout, "=== "+res.Task.Label+"\n")
+		_, _ = opts.Stdout.Write(buf.Bytes())
+	}
+}
+
+// lineWriter writes whole lines with a prefix, so lines of parallel tasks do not mix
+// lineWriter 以前缀写入完整的行，使并行任务的行不会混杂
+type lineWriter struct {
+	w      io.Writer
+	prefix string
+	mutex  *sync.Mutex
+	buf    []byte
+}
+
+func (l *lineWriter) Write(data []byte) (int, error) {
+	l.buf = append(l.buf, data...)
+	for {
+		idx := bytes.IndexByte(l.buf, '\n')
+		if idx < 0 {
+			return len(data), nil
+		}
+		l.writeLine(l.buf[:idx+1])
+		l.buf = l.buf[idx+1:]
+	}
+}
+
+// Flush writes the trailing partial line, ending it with a newline
+// Flush 写出末尾不完整的行，并补上换行
+func (l *lineWriter) Flush() {
+	if len(l.buf) > 0 {
+		l.writeLine(append(l.buf, '\n'))
+		l.buf = nil
+	}
+}
+
+func (l *lineWriter) writeLine(line []byte) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	_, _ = io.WriteString(l.w, l.prefix)
+	_, _ = l.w.Write(line)
+}
@@ -0,0 +1,95 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// setupTasks creates DIRs a, b and c with a marker file naming each
+// setupTasks 创建 a、b、c 三个 DIR，各含一个写有其名称的标记文件
+func setupTasks(t *testing.T) []Task {
+	root := t.TempDir()
+	var tasks []Task
+	for _, name := range []string{"a", "b", "c"} {
+		dir := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "name"), []byte(name), 0644))
+		tasks = append(tasks, Task{Dir: dir, Label: name})
+	}
+	return tasks
+}
+
+// TestRun_Prefixed tests each output line is prefixed with the task label
+// TestRun_Prefixed 测试每行输出都带有任务标签前缀
+func TestRun_Prefixed(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	results := Run(context.Background(), setupTasks(t), "sh", []string{"-c", "cat name; echo; echo err >&2"}, Options{
+		Parallel: 1,
+		Stdout:   &stdout,
+		Stderr:   &stderr,
+	})
+	require.Equal(t, "[a] a\n[b] b\n[c] c\n", stdout.String())
+	require.Equal(t, "[a] err\n[b] err\n[c] err\n", stderr.String())
+	for _, res := range results {
+		require.Equal(t, StatusOK, res.Status)
+		require.Equal(t, 0, res.ExitCode)
+		require.NoError(t, res.Err)
+	}
+}
+
+// TestRun_Grouped tests output is printed per task under a header
+// TestRun_Grouped 测试输出按任务在标题下整体打印
+func TestRun_Grouped(t *testing.T) {
+	var stdout bytes.Buffer
+	Run(context.Background(), setupTasks(t)[:2], "cat", []string{"name"}, Options{
+		Parallel: 1,
+		Grouped:  true,
+		Stdout:   &stdout,
+		Stderr:   &stdout,
+	})
+	require.Equal(t, "=== a\na\n=== b\nb\n", stdout.String())
+}
+
+// TestRun_KeepGoing tests a failure skips remaining tasks unless KeepGoing is set
+// TestRun_KeepGoing 测试未设置 KeepGoing 时失败会跳过剩余任务
+func TestRun_KeepGoing(t *testing.T) {
+	script := `test "$(cat name)" != a || exit 3`
+	tasks := setupTasks(t)
+
+	results := Run(context.Background(), tasks, "sh", []string{"-c", script}, Options{
+		Parallel: 1,
+		Stdout:   &bytes.Buffer{},
+		Stderr:   &bytes.Buffer{},
+	})
+	require.Equal(t, StatusFailed, results[0].Status)
+	require.Equal(t, 3, results[0].ExitCode)
+	require.Equal(t, StatusSkipped, results[1].Status)
+	require.Equal(t, StatusSkipped, results[2].Status)
+
+	results = Run(context.Background(), tasks, "sh", []string{"-c", script}, Options{
+		Parallel:  2,
+		KeepGoing: true,
+		Stdout:    &bytes.Buffer{},
+		Stderr:    &bytes.Buffer{},
+	})
+	require.Equal(t, StatusFailed, results[0].Status)
+	require.Equal(t, StatusOK, results[1].Status)
+	require.Equal(t, StatusOK, results[2].Status)
+}
+
+// TestRun_NotFound tests a missing command is reported as failed with exit code -1
+// TestRun_NotFound 测试不存在的命令报告为失败且退出码为 -1
+func TestRun_NotFound(t *testing.T) {
+	results := Run(context.Background(), setupTasks(t)[:1], "go-work-missing-command", nil, Options{
+		Stdout: &bytes.Buffer{},
+		Stderr: &bytes.Buffer{},
+	})
+	require.Equal(t, StatusFailed, results[0].Status)
+	require.Equal(t, -1, results[0].ExitCode)
+	require.Error(t, results[0].Err)
+}
@@ -0,0 +1,48 @@
+// Package testfs: File and git repo fixtures shared by the tests of several packages
+// Each helper fails the test at once on errors, so callers need no error handling
+//
+// testfs: 多个包的测试共用的文件和 git 仓库夹具
+// 每个辅助函数出错时立即使测试失败，因此调用方无需处理错误
+package testfs
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// WriteFile writes content to path, creating parent DIRs
+// WriteFile 将 content 写入 path，并创建父 DIR
+func WriteFile(t *testing.T, path string, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+// WriteGoMod writes the go.mod of dir declaring modulePath and go 1.22, followed by content
+// WriteGoMod 写入 dir 的 go.mod，声明 modulePath 和 go 1.22，其后跟随 content
+func WriteGoMod(t *testing.T, dir string, modulePath string, content string) {
+	t.Helper()
+	WriteFile(t, filepath.Join(dir, "go.mod"), "module "+modulePath+"\n\ngo 1.22\n"+content)
+}
+
+// RunGit runs git in dir with a fixed identity
+// RunGit 以固定身份在 dir 中运行 git
+func RunGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+// WriteModule writes dir as a module declaring modulePath, with a go.mod and a main.go
+// WriteModule 将 dir 写成声明 modulePath 的模块，包含 go.mod 和 main.go
+func WriteModule(t *testing.T, dir string, modulePath string) {
+	t.Helper()
+	WriteGoMod(t, dir, modulePath, "")
+	WriteFile(t, filepath.Join(dir, "main.go"), "package main\n\nfunc main() {}\n")
+}
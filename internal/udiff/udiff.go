@@ -0,0 +1,152 @@
+// Package udiff: Unified diffs of small text files
+// Used by dry-run modes to preview file rewrites
+//
+// udiff: 小型文本文件的统一 diff
+// 供 dry-run 模式预览文件改写
+package udiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the count of unchanged lines shown around each change
+// contextLines 是每处改动周围显示的未改动行数
+const contextLines = 3
+
+// opKind marks how a line moves from old to new
+// opKind 标记行从 old 到 new 的变化方式
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+// op is one line of the edit script
+// op 是编辑脚本中的一行
+type op struct {
+	kind opKind
+	line string
+	oldN int // 1-based line in old, for equal and delete // old 中从 1 开始的行号，用于 equal 和 delete
+	newN int // 1-based line in new, for equal and insert // new 中从 1 开始的行号，用于 equal 和 insert
+}
+
+// Unified returns the unified diff turning old into new, blank when they are equal
+// Unified 返回将 old 变为 new 的统一 diff，二者相同时返回空
+func Unified(oldName string, newName string, old []byte, new []byte) string {
+	if string(old) == string(new) {
+		return ""
+	}
+	ops := editScript(splitLines(string(old)), splitLines(string(new)))
+
+	var sb strings.Builder
+	sb.WriteString("--- " + oldName + "\n")
+	sb.WriteString("+++ " + newName + "\n")
+	for start := 0; start < len(ops); {
+		if ops[start].kind == opEqual {
+			start++
+			continue
+		}
+		// Extend the hunk while changes are close enough to share context
+		// 当改动足够接近可共享上下文时扩展 hunk
+		end := start
+		for idx := start; idx < len(ops); idx++ {
+			if ops[idx].kind != opEqual {
+				end = idx + 1
+			} else if idx-end >= 2*contextLines {
+				break
+			}
+		}
+		from := max(start-contextLines, 0)
+		to := min(end+contextLines, len(ops))
+		writeHunk(&sb, ops[from:to])
+		start = to
+	}
+	return sb.String()
+}
+
+// writeHunk writes one hunk header and its lines
+// writeHunk 写入一个 hunk 头及其行
+func writeHunk(sb *strings.Builder, ops []op) {
+	oldStart, newStart, oldCount, newCount := 0, 0, 0, 0
+	for _, item := range ops {
+		if item.kind != opInsert {
+			if oldCount == 0 {
+				oldStart = item.oldN
+			}
+			oldCount++
+		}
+		if item.kind != opDelete {
+			if newCount == 0 {
+				newStart = item.newN
+			}
+			newCount++
+		}
+	}
+	// Empty ranges point at the line before the change
+	// 空范围指向改动之前的那一行
+	if oldCount == 0 {
+		oldStart = ops[0].oldN
+	}
+	if newCount == 0 {
+		newStart = ops[0].newN
+	}
+	fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+	for _, item := range ops {
+		switch item.kind {
+		case opEqual:
+			sb.WriteString(" " + item.line + "\n")
+		case opDelete:
+			sb.WriteString("-" + item.line + "\n")
+		case opInsert:
+			sb.WriteString("+" + item.line + "\n")
+		}
+	}
+}
+
+// editScript computes a shortest edit script through the longest common subsequence
+// editScript 通过最长公共子序列计算最短编辑脚本
+func editScript(a []string, b []string) []op {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []op
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, op{kind: opEqual, line: a[i], oldN: i + 1, newN: j + 1})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, op{kind: opDelete, line: a[i], oldN: i + 1, newN: j})
+			i++
+		default:
+			ops = append(ops, op{kind: opInsert, line: b[j], oldN: i, newN: j + 1})
+			j++
+		}
+	}
+	return ops
+}
+
+// splitLines splits text into lines without their line breaks
+// splitLines 将文本拆分为不含换行符的行
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
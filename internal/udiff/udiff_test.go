@@ -0,0 +1,40 @@
+package udiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestUnified tests unified diff output
+// TestUnified 测试统一 diff 输出
+func TestUnified(t *testing.T) {
+	old := "module a\n\ngo 1.21\n\nrequire (\n\tx v1\n\ty v1\n)\n"
+	new := "module a\n\ngo 1.22\n\nrequire (\n\tx v1\n\ty v2\n)\n"
+
+	diff := Unified("a/go.mod", "b/go.mod", []byte(old), []byte(new))
+	t.Log(diff)
+	require.Equal(t, `--- a/go.mod
++++ b/go.mod
+@@ -1,8 +1,8 @@
+ module a
+ 
+-go 1.21
++go 1.22
+ 
+ require (
+ 	x v1
+-	y v1
++	y v2
+ )
+`, diff)
+
+	require.Empty(t, Unified("a", "b", []byte(old), []byte(old)))
+}
+
+// TestUnified_NewFile tests diffs against a blank file
+// TestUnified_NewFile 测试与空文件的 diff
+func TestUnified_NewFile(t *testing.T) {
+	diff := Unified("/dev/null", "go.work", nil, []byte("go 1.22\n\nuse ./a\n"))
+	require.Equal(t, "--- /dev/null\n+++ go.work\n@@ -0,0 +1,3 @@\n+go 1.22\n+\n+use ./a\n", diff)
+}
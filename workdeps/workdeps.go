@@ -0,0 +1,197 @@
+// Package workdeps: External dependency versions across the modules of a workspace
+// Aggregates the require directives of every go.mod and plans version updates
+//
+// workdeps: 工作区各模块的外部依赖版本
+// 汇总每个 go.mod 的 require 指令并规划版本更新
+package workdeps
+
+import (
+	"sort"
+
+	"github.com/go-mate/go-work/workspath"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// Usage is one module requiring a dependency
+// Usage 是依赖某个依赖项的一个模块
+type Usage struct {
+	Module   string `json:"module"`             // Module path of the requiring module // 依赖方的模块路径
+	Dir      string `json:"dir"`                // DIR of the requiring module // 依赖方模块的 DIR
+	Version  string `json:"version"`            // Required version // 依赖的版本
+	Indirect bool   `json:"indirect,omitempty"` // Marked "// indirect" // 标记为 "// indirect"
+}
+
+// Dependency is an external module required by workspace modules
+// Dependency 是工作区模块依赖的外部模块
+type Dependency struct {
+	Path     string   `json:"path"`     // Module path of the dependency // 依赖项的模块路径
+	Versions []string `json:"versions"` // Distinct required versions, lowest first // 不同的依赖版本，从低到高
+	Usages   []Usage  `json:"usages"`   // Requiring modules in scan order // 按扫描顺序排列的依赖方
+}
+
+// Mismatched reports whether the modules require more than one version
+// Mismatched 判断各模块是否依赖了多个版本
+func (d *Dependency) Mismatched() bool {
+	return len(d.Versions) > 1
+}
+
+// Highest returns the highest required version
+// Highest 返回最高的依赖版本
+func (d *Dependency) Highest() string {
+	return d.Versions[len(d.Versions)-1]
+}
+
+// Collect aggregates the require directives of modules, sorted by dependency path
+// Requires of other workspace modules are left out, since those follow the workspace rather than a release
+//
+// Collect 汇总 modules 的 require 指令，按依赖路径排序
+// 不包含对其它工作区模块的依赖，因为它们跟随工作区而不是发布版本
+func Collect(modules []workspath.Module) []*Dependency {
+	local := map[string]bool{}
+	for _, module := range modules {
+		if module.ModulePath != "" {
+			local[module.ModulePath] = true
+		}
+	}
+
+	index := map[string]*Dependency{}
+	for _, module := range modules {
+		for _, require := range module.Require {
+			if local[require.Path] {
+				continue
+			}
+			dep, ok := index[require.Path]
+			if !ok {
+				dep = &Dependency{Path: require.Path}
+				index[require.Path] = dep
+			}
+			dep.Usages = append(dep.Usages, Usage{
+				Module:   module.ModulePath,
+				Dir:      module.Path,
+				Version:  require.Version,
+				Indirect: require.Indirect,
+			})
+		}
+	}
+
+	deps := make([]*Dependency, 0, len(index))
+	for _, dep := range index {
+		seen := map[string]bool{}
+		for _, usage := range dep.Usages {
+			if !seen[usage.Version] {
+				seen[usage.Version] = true
+				dep.Versions = append(dep.Versions, usage.Version)
+			}
+		}
+		semver.Sort(dep.Versions)
+		deps = append(deps, dep)
+	}
+	sort.Slice(deps, func(i, j int) bool {
+		return deps[i].Path < deps[j].Path
+	})
+	return deps
+}
+
+// Update moves the require of one dependency in one module to another version
+// Update 将某个模块中某个依赖项的 require 改为另一个版本
+type Update struct {
+	Module string `json:"module"` // Module path of the requiring module // 依赖方的模块路径
+	Dir    string `json:"dir"`    // DIR of the requiring module // 依赖方模块的 DIR
+	Path   string `json:"path"`   // Module path of the dependency // 依赖项的模块路径
+	From   string `json:"from"`   // Current version // 当前版本
+	To     string `json:"to"`     // Target version // 目标版本
+}
+
+// Align plans the updates that bring each mismatched dependency to one version
+// The target is the pinned version when pins has the path, else the highest required version
+// Pinned dependencies are aligned even when they match, so every module ends at the pin
+//
+// Align 规划使每个版本不一致的依赖项统一到一个版本的更新
+// pins 中有该路径时目标为固定版本，否则为最高依赖版本
+// 固定的依赖项即使版本一致也会对齐，使每个模块最终都使用固定版本
+func Align(deps []*Dependency, pins map[string]string) []*Update {
+	var updates []*Update
+	for _, dep := range deps {
+		target, pinned := pins[dep.Path]
+		if !pinned {
+			if !dep.Mismatched() {
+				continue
+			}
+			target = dep.Highest()
+		}
+		for _, usage := range dep.Usages {
+			if usage.Version != target {
+				updates = append(updates, &Update{
+					Module: usage.Module,
+					Dir:    usage.Dir,
+					Path:   dep.Path,
+					From:   usage.Version,
+					To:     target,
+				})
+			}
+		}
+	}
+	return updates
+}
+
+// Set plans the updates that move every module requiring path to version
+// Modules not requiring path are left alone, so no new require is added
+//
+// Set 规划将每个依赖 path 的模块改为 version 的更新
+// 不依赖 path 的模块保持不变，因此不会新增 require
+func Set(deps []*Dependency, path string, version string) []*Update {
+	var updates []*Update
+	for _, dep := range deps {
+		if dep.Path != path {
+			continue
+		}
+		for _, usage := range dep.Usages {
+			if usage.Version != version {
+				updates = append(updates, &Update{
+					Module: usage.Module,
+					Dir:    usage.Dir,
+					Path:   path,
+					From:   usage.Version,
+					To:     version,
+				})
+			}
+		}
+	}
+	return updates
+}
+
+// Apply computes the go.mod changes of updates, one change per module DIR in order of first appearance
+// Requires are rewritten with modfile.AddRequire, keeping comments like "// indirect"
+// Nothing is written, call Write on each change to save it
+//
+// Apply 计算 updates 对应的 go.mod 变更，每个模块 DIR 一个变更，按首次出现的顺序排列
+// 使用 modfile.AddRequire 改写 require，保留 "// indirect" 等注释
+// 不写入任何内容，对每个变更调用 Write 进行保存
+func Apply(updates []*Update) ([]*workspath.Change, error) {
+	var dirs []string
+	groups := map[string][]*Update{}
+	for _, update := range updates {
+		if _, ok := groups[update.Dir]; !ok {
+			dirs = append(dirs, update.Dir)
+		}
+		groups[update.Dir] = append(groups[update.Dir], update)
+	}
+
+	changes := make([]*workspath.Change, 0, len(dirs))
+	for _, dir := range dirs {
+		change, err := workspath.EditGoMod(dir, func(modFile *modfile.File) error {
+			for _, update := range groups[dir] {
+				if err := modFile.AddRequire(update.Path, update.To); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
@@ -0,0 +1,97 @@
+package workdeps_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-mate/go-work/internal/testfs"
+	"github.com/go-mate/go-work/workdeps"
+	"github.com/go-mate/go-work/workspath"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+// setupModules creates three modules requiring zap and testify at different versions
+// setupModules 创建三个以不同版本依赖 zap 和 testify 的模块
+func setupModules(t *testing.T) (string, []workspath.Module) {
+	tempDIR := t.TempDir()
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "api"), "example.com/api", "\nrequire (\n\texample.com/lib v0.0.0\n\tgo.uber.org/zap v1.24.0\n)\n")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "lib"), "example.com/lib", "\nrequire (\n\tgithub.com/stretchr/testify v1.9.0\n\tgo.uber.org/zap v1.27.0 // indirect\n)\n")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "tool"), "example.com/tool", "\nrequire github.com/stretchr/testify v1.9.0\n")
+	modules := rese.V1(workspath.ScanModules(tempDIR, workspath.ScanDeep()))
+	return tempDIR, modules
+}
+
+// TestCollect tests requires are grouped per dependency with workspace modules left out
+// TestCollect 测试 require 按依赖项分组，且不包含工作区模块
+func TestCollect(t *testing.T) {
+	tempDIR, modules := setupModules(t)
+
+	deps := workdeps.Collect(modules)
+	require.Len(t, deps, 2)
+
+	require.Equal(t, "github.com/stretchr/testify", deps[0].Path)
+	require.Equal(t, []string{"v1.9.0"}, deps[0].Versions)
+	require.False(t, deps[0].Mismatched())
+
+	require.Equal(t, "go.uber.org/zap", deps[1].Path)
+	require.Equal(t, []string{"v1.24.0", "v1.27.0"}, deps[1].Versions)
+	require.True(t, deps[1].Mismatched())
+	require.Equal(t, "v1.27.0", deps[1].Highest())
+	require.Equal(t, []workdeps.Usage{
+		{Module: "example.com/api", Dir: filepath.Join(tempDIR, "api"), Version: "v1.24.0"},
+		{Module: "example.com/lib", Dir: filepath.Join(tempDIR, "lib"), Version: "v1.27.0", Indirect: true},
+	}, deps[1].Usages)
+}
+
+// TestAlign tests lagging modules move to the highest or pinned version
+// TestAlign 测试落后的模块升级到最高版本或固定版本
+func TestAlign(t *testing.T) {
+	tempDIR, modules := setupModules(t)
+	deps := workdeps.Collect(modules)
+
+	updates := workdeps.Align(deps, nil)
+	require.Equal(t, []*workdeps.Update{
+		{Module: "example.com/api", Dir: filepath.Join(tempDIR, "api"), Path: "go.uber.org/zap", From: "v1.24.0", To: "v1.27.0"},
+	}, updates)
+
+	updates = workdeps.Align(deps, map[string]string{"github.com/stretchr/testify": "v1.10.0", "go.uber.org/zap": "v1.26.0"})
+	require.Len(t, updates, 4)
+	require.Equal(t, "github.com/stretchr/testify", updates[0].Path)
+	require.Equal(t, "v1.10.0", updates[0].To)
+	require.Equal(t, "v1.26.0", updates[3].To)
+
+	changes := rese.V1(workdeps.Apply(updates))
+	require.Len(t, changes, 3)
+	require.Equal(t, filepath.Join(tempDIR, "lib", "go.mod"), changes[0].Path)
+	require.Equal(t, "module example.com/lib\n\ngo 1.22\n\nrequire (\n\tgithub.com/stretchr/testify v1.10.0\n\tgo.uber.org/zap v1.26.0 // indirect\n)\n", string(changes[0].New))
+
+	for _, change := range changes {
+		require.NoError(t, change.Write())
+	}
+	modules = rese.V1(workspath.ScanModules(tempDIR, workspath.ScanDeep()))
+	for _, dep := range workdeps.Collect(modules) {
+		require.False(t, dep.Mismatched())
+	}
+	require.Empty(t, workdeps.Align(workdeps.Collect(modules), nil))
+}
+
+// TestSet tests only modules already requiring the dependency are updated
+// TestSet 测试只更新已经依赖该依赖项的模块
+func TestSet(t *testing.T) {
+	tempDIR, modules := setupModules(t)
+	deps := workdeps.Collect(modules)
+
+	updates := workdeps.Set(deps, "github.com/stretchr/testify", "v1.10.0")
+	require.Equal(t, []*workdeps.Update{
+		{Module: "example.com/lib", Dir: filepath.Join(tempDIR, "lib"), Path: "github.com/stretchr/testify", From: "v1.9.0", To: "v1.10.0"},
+		{Module: "example.com/tool", Dir: filepath.Join(tempDIR, "tool"), Path: "github.com/stretchr/testify", From: "v1.9.0", To: "v1.10.0"},
+	}, updates)
+
+	require.Empty(t, workdeps.Set(deps, "github.com/stretchr/testify", "v1.9.0"))
+	require.Empty(t, workdeps.Set(deps, "example.com/unused", "v1.0.0"))
+
+	changes := rese.V1(workdeps.Apply(updates))
+	require.Len(t, changes, 2)
+	require.Equal(t, "module example.com/tool\n\ngo 1.22\n\nrequire github.com/stretchr/testify v1.10.0\n", string(changes[1].New))
+}
@@ -0,0 +1,396 @@
+// Package workgraph: Dependency graph between the modules of a workspace
+// Links modules through go.mod require directives and local replace targets
+// Provides topological ordering, cycle detection and reverse dependencies
+//
+// workgraph: 工作区内模块之间的依赖图
+// 通过 go.mod 的 require 指令和本地 replace 目标连接模块
+// 提供拓扑排序、环检测和反向依赖
+package workgraph
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-mate/go-work/workspath"
+	"golang.org/x/mod/modfile"
+)
+
+// Edge is a dependency of one workspace module on another
+// Edge 是一个工作区模块对另一个工作区模块的依赖
+type Edge struct {
+	From     string `json:"from"`               // Module path of the dependent // 依赖方的模块路径
+	To       string `json:"to"`                 // Module path of the dependency // 被依赖方的模块路径
+	Replaced bool   `json:"replaced,omitempty"` // Linked through a local replace // 通过本地 replace 连接
+}
+
+// Graph holds the workspace modules and the edges between them
+// Modules are identified by module path, the first module wins when paths repeat
+//
+// Graph 保存工作区模块以及它们之间的边
+// 模块以模块路径标识，路径重复时以第一个模块为准
+type Graph struct {
+	modules []workspath.Module          // Modules in scan order // 按扫描顺序排列的模块
+	index   map[string]int              // Module path to position in modules // 模块路径到 modules 中位置的映射
+	deps    map[string]map[string]*Edge // Edges keyed by from and to // 以 from 和 to 为键的边
+	rdeps   map[string]map[string]bool  // Reverse edges keyed by to and from // 以 to 和 from 为键的反向边
+}
+
+// New builds the graph of modules
+// A require links to the workspace module with that path, or to the module in the DIR of a local replace
+// Modules without a parsed go.mod are left out
+//
+// New 构建 modules 的依赖图
+// require 连接到具有该路径的工作区模块，或本地 replace 指向 DIR 中的模块
+// 没有成功解析 go.mod 的模块不会加入
+func New(modules []workspath.Module) *Graph {
+	g := &Graph{
+		index: map[string]int{},
+		deps:  map[string]map[string]*Edge{},
+		rdeps: map[string]map[string]bool{},
+	}
+	dirs := map[string]string{}
+	for _, module := range modules {
+		if module.File == nil || module.ModulePath == "" {
+			continue
+		}
+		if _, ok := g.index[module.ModulePath]; ok {
+			continue
+		}
+		g.index[module.ModulePath] = len(g.modules)
+		g.modules = append(g.modules, module)
+		dirs[filepath.Clean(module.Path)] = module.ModulePath
+	}
+
+	for _, module := range g.modules {
+		for _, require := range module.Require {
+			if target, ok := localReplace(module, require, dirs); ok {
+				g.addEdge(module.ModulePath, target, true)
+			} else if _, ok := g.index[require.Path]; ok {
+				g.addEdge(module.ModulePath, require.Path, false)
+			}
+		}
+	}
+	return g
+}
+
+// localReplace returns the workspace module that a local replace of require points at
+// localReplace 返回 require 的本地 replace 所指向的工作区模块
+func localReplace(module workspath.Module, require workspath.Require, dirs map[string]string) (string, bool) {
+	for _, replace := range module.Replace {
+		if replace.Old.Path != require.Path || (replace.Old.Version != "" && replace.Old.Version != require.Version) {
+			continue
+		}
+		if !modfile.IsDirectoryPath(replace.New.Path) {
+			return "", false
+		}
+		dir := replace.New.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(module.Path, dir)
+		}
+		target, ok := dirs[filepath.Clean(dir)]
+		return target, ok
+	}
+	return "", false
+}
+
+func (g *Graph) addEdge(from, to string, replaced bool) {
+	if g.deps[from] == nil {
+		g.deps[from] = map[string]*Edge{}
+	}
+	if edge, ok := g.deps[from][to]; ok {
+		edge.Replaced = edge.Replaced || replaced
+		return
+	}
+	g.deps[from][to] = &Edge{From: from, To: to, Replaced: replaced}
+	if g.rdeps[to] == nil {
+		g.rdeps[to] = map[string]bool{}
+	}
+	g.rdeps[to][from] = true
+}
+
+// Modules returns the modules in the graph in scan order
+// Modules 按扫描顺序返回图中的模块
+func (g *Graph) Modules() []workspath.Module {
+	return g.modules
+}
+
+// Module returns the module with modulePath
+// Module 返回模块路径为 modulePath 的模块
+func (g *Graph) Module(modulePath string) (workspath.Module, bool) {
+	idx, ok := g.index[modulePath]
+	if !ok {
+		return workspath.Module{}, false
+	}
+	return g.modules[idx], true
+}
+
+// Edges returns all edges, sorted by from and then to in scan order
+// Edges 返回全部边，先按 from 再按 to 的扫描顺序排列
+func (g *Graph) Edges() []*Edge {
+	var edges []*Edge
+	for _, module := range g.modules {
+		for _, to := range g.Deps(module.ModulePath) {
+			edges = append(edges, g.deps[module.ModulePath][to])
+		}
+	}
+	return edges
+}
+
+// Deps returns the workspace modules that modulePath depends on directly, in scan order
+// Deps 按扫描顺序返回 modulePath 直接依赖的工作区模块
+func (g *Graph) Deps(modulePath string) []string {
+	return g.ordered(func(to string) bool {
+		_, ok := g.deps[modulePath][to]
+		return ok
+	})
+}
+
+// ReverseDeps returns the workspace modules that depend on modulePath directly, in scan order
+// ReverseDeps 按扫描顺序返回直接依赖 modulePath 的工作区模块
+func (g *Graph) ReverseDeps(modulePath string) []string {
+	return g.ordered(func(from string) bool {
+		return g.rdeps[modulePath][from]
+	})
+}
+
+// ordered returns the module paths matching keep, in scan order
+// ordered 按扫描顺序返回满足 keep 的模块路径
+func (g *Graph) ordered(keep func(modulePath string) bool) []string {
+	var paths []string
+	for _, module := range g.modules {
+		if keep(module.ModulePath) {
+			paths = append(paths, module.ModulePath)
+		}
+	}
+	return paths
+}
+
+// CycleError reports the dependency cycles that prevent a topological order
+// CycleError 报告阻碍拓扑排序的依赖环
+type CycleError struct {
+	Cycles [][]string // Module paths of each cycle in scan order // 每个环中按扫描顺序排列的模块路径
+	Chains [][]string // Each cycle as a chain of edges, ending where it starts // 每个环的边链，以起点结束
+}
+
+func (e *CycleError) Error() string {
+	parts := make([]string, 0, len(e.Chains))
+	for _, chain := range e.Chains {
+		parts = append(parts, strings.Join(chain, " -> "))
+	}
+	return fmt.Sprintf("dependency cycles: %s", strings.Join(parts, "; "))
+}
+
+// Sort returns the module paths with dependencies before dependents
+// Modules without order between them keep the scan order
+// With cycles all modules are still returned, along with a CycleError
+//
+// Sort 返回依赖在前、依赖方在后的模块路径
+// 彼此无顺序关系的模块保持扫描顺序
+// 存在环时仍返回全部模块，同时返回 CycleError
+func (g *Graph) Sort() ([]string, error) {
+	done := map[string]bool{}
+	visiting := map[string]bool{}
+	order := make([]string, 0, len(g.modules))
+	var visit func(modulePath string)
+	visit = func(modulePath string) {
+		if done[modulePath] || visiting[modulePath] {
+			return
+		}
+		visiting[modulePath] = true
+		for _, dep := range g.Deps(modulePath) {
+			visit(dep)
+		}
+		visiting[modulePath] = false
+		done[modulePath] = true
+		order = append(order, modulePath)
+	}
+	for _, module := range g.modules {
+		visit(module.ModulePath)
+	}
+	if err := g.cycleError(); err != nil {
+		return order, err
+	}
+	return order, nil
+}
+
+// Levels groups the module paths into topological levels, in scan order inside each level
+// Level 0 holds modules without workspace dependencies, each later level depends only on earlier ones
+// So the modules of one level can be handled in parallel once the earlier levels are done
+//
+// Levels 将模块路径分组为拓扑层级，每层内部保持扫描顺序
+// 第 0 层为没有工作区依赖的模块，之后每层只依赖更早的层
+// 因此在更早的层完成后，同一层的模块可以并行处理
+func (g *Graph) Levels() ([][]string, error) {
+	order, err := g.Sort()
+	if err != nil {
+		return nil, err
+	}
+	depth := map[string]int{}
+	var levels [][]string
+	for _, modulePath := range order {
+		level := 0
+		for _, dep := range g.Deps(modulePath) {
+			level = max(level, depth[dep]+1)
+		}
+		depth[modulePath] = level
+		if level == len(levels) {
+			levels = append(levels, nil)
+		}
+	}
+	for _, module := range g.modules {
+		level := depth[module.ModulePath]
+		levels[level] = append(levels[level], module.ModulePath)
+	}
+	return levels, nil
+}
+
+// cycleError returns a CycleError listing each cycle and its chain, nil without cycles
+// cycleError 返回列出每个环及其边链的 CycleError，没有环时返回 nil
+func (g *Graph) cycleError() *CycleError {
+	cycles := g.Cycles()
+	if len(cycles) == 0 {
+		return nil
+	}
+	chains := make([][]string, 0, len(cycles))
+	for _, cycle := range cycles {
+		chains = append(chains, g.chain(cycle))
+	}
+	return &CycleError{Cycles: cycles, Chains: chains}
+}
+
+// chain returns the shortest path of edges from the first module of cycle back to itself
+// chain 返回从 cycle 的第一个模块回到其自身的最短边路径
+func (g *Graph) chain(cycle []string) []string {
+	start := cycle[0]
+	members := map[string]bool{}
+	for _, modulePath := range cycle {
+		members[modulePath] = true
+	}
+	// Breadth first inside the cycle, parents record how each module was reached
+	// 在环内广度优先搜索，parents 记录到达每个模块的来源
+	parents := map[string]string{}
+	queue := []string{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dep := range g.Deps(current) {
+			if dep == start {
+				chain := []string{start}
+				for step := current; step != start; step = parents[step] {
+					chain = append(chain, step)
+				}
+				// Reverse the walk back from current, then close the loop at start
+				// 反转从 current 回溯的路径，然后在 start 处闭合
+				for i, j := 1, len(chain)-1; i < j; i, j = i+1, j-1 {
+					chain[i], chain[j] = chain[j], chain[i]
+				}
+				return append(chain, start)
+			}
+			if _, seen := parents[dep]; seen || !members[dep] {
+				continue
+			}
+			parents[dep] = current
+			queue = append(queue, dep)
+		}
+	}
+	return append(cycle, start)
+}
+
+// Cycles returns the groups of modules that depend on each other, including self dependencies
+// Cycles 返回相互依赖的模块组，包括依赖自身的模块
+func (g *Graph) Cycles() [][]string {
+	// Tarjan's strongly connected components
+	// Tarjan 强连通分量算法
+	index := map[string]int{}
+	lowLink := map[string]int{}
+	onStack := map[string]bool{}
+	var stack []string
+	var cycles [][]string
+	var connect func(modulePath string)
+	connect = func(modulePath string) {
+		index[modulePath] = len(index)
+		lowLink[modulePath] = index[modulePath]
+		stack = append(stack, modulePath)
+		onStack[modulePath] = true
+		for _, dep := range g.Deps(modulePath) {
+			if _, ok := index[dep]; !ok {
+				connect(dep)
+				lowLink[modulePath] = min(lowLink[modulePath], lowLink[dep])
+			} else if onStack[dep] {
+				lowLink[modulePath] = min(lowLink[modulePath], index[dep])
+			}
+		}
+		if lowLink[modulePath] != index[modulePath] {
+			return
+		}
+		var group []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			group = append(group, top)
+			if top == modulePath {
+				break
+			}
+		}
+		if len(group) > 1 || g.deps[modulePath][modulePath] != nil {
+			sort.Slice(group, func(i, j int) bool {
+				return g.index[group[i]] < g.index[group[j]]
+			})
+			cycles = append(cycles, group)
+		}
+	}
+	for _, module := range g.modules {
+		if _, ok := index[module.ModulePath]; !ok {
+			connect(module.ModulePath)
+		}
+	}
+	sort.SliceStable(cycles, func(i, j int) bool {
+		return g.index[cycles[i][0]] < g.index[cycles[j][0]]
+	})
+	return cycles
+}
+
+// Owner returns the module owning file, the deepest module whose DIR contains it
+// The file does not need to exist, so deleted files still map to their module
+//
+// Owner 返回拥有 file 的模块，即 DIR 包含该文件的最深模块
+// 文件无需存在，因此已删除的文件仍能对应到其模块
+func (g *Graph) Owner(file string) (string, bool) {
+	file = filepath.Clean(file)
+	var owner string
+	depth := -1
+	for _, module := range g.modules {
+		rel, err := filepath.Rel(filepath.Clean(module.Path), file)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if size := len(filepath.Clean(module.Path)); size > depth {
+			owner, depth = module.ModulePath, size
+		}
+	}
+	return owner, depth >= 0
+}
+
+// Dependents returns modulePaths together with every module depending on them directly or indirectly, in scan order
+// Dependents 按扫描顺序返回 modulePaths 以及直接或间接依赖它们的全部模块
+func (g *Graph) Dependents(modulePaths ...string) []string {
+	seen := map[string]bool{}
+	queue := append([]string{}, modulePaths...)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if seen[current] {
+			continue
+		}
+		seen[current] = true
+		for from := range g.rdeps[current] {
+			queue = append(queue, from)
+		}
+	}
+	return g.ordered(func(modulePath string) bool {
+		return seen[modulePath]
+	})
+}
@@ -0,0 +1,199 @@
+package workgraph_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-mate/go-work/internal/testfs"
+	"github.com/go-mate/go-work/workgraph"
+	"github.com/go-mate/go-work/workspath"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// scanGraph builds the graph of the modules under root
+// scanGraph 构建 root 下模块的依赖图
+func scanGraph(t *testing.T, root string) *workgraph.Graph {
+	modules := rese.V1(workspath.ScanModules(root, workspath.WithCurrentPackage(), workspath.ScanDeep()))
+	return workgraph.New(modules)
+}
+
+// TestNew tests edges from require directives and local replace targets
+// TestNew 测试由 require 指令和本地 replace 目标得到的边
+func TestNew(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "test-workgraph-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "api"), "example.com/api", "\nrequire (\n\texample.com/lib v1.0.0\n\texample.com/util v1.0.0\n\tgithub.com/outside/dep v1.2.3\n)\n\nreplace example.com/util => ../tools/util\n")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "lib"), "example.com/lib", "\nrequire example.com/util/v2 v2.0.0\n\nreplace example.com/util/v2 => ../tools/util\n")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "tools", "util"), "example.com/util/v2", "")
+
+	graph := scanGraph(t, tempDIR)
+	require.Len(t, graph.Modules(), 3)
+	require.Equal(t, []string{"example.com/lib", "example.com/util/v2"}, graph.Deps("example.com/api"))
+	require.Equal(t, []string{"example.com/util/v2"}, graph.Deps("example.com/lib"))
+	require.Empty(t, graph.Deps("example.com/util/v2"))
+	require.Equal(t, []string{"example.com/api", "example.com/lib"}, graph.ReverseDeps("example.com/util/v2"))
+	require.Equal(t, []*workgraph.Edge{
+		{From: "example.com/api", To: "example.com/lib"},
+		{From: "example.com/api", To: "example.com/util/v2", Replaced: true},
+		{From: "example.com/lib", To: "example.com/util/v2", Replaced: true},
+	}, graph.Edges())
+
+	order, err := graph.Sort()
+	require.NoError(t, err)
+	require.Equal(t, []string{"example.com/util/v2", "example.com/lib", "example.com/api"}, order)
+	require.Empty(t, graph.Cycles())
+
+	module, ok := graph.Module("example.com/lib")
+	require.True(t, ok)
+	require.Equal(t, filepath.Join(tempDIR, "lib"), module.Path)
+}
+
+// TestGraph_Cycles tests cycles are reported while Sort still returns every module
+// TestGraph_Cycles 测试环会被报告，同时 Sort 仍返回全部模块
+func TestGraph_Cycles(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "test-workgraph-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "a"), "example.com/a", "\nrequire example.com/b v1.0.0\n")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "b"), "example.com/b", "\nrequire example.com/a v1.0.0\n")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "c"), "example.com/c", "\nrequire example.com/a v1.0.0\n")
+
+	graph := scanGraph(t, tempDIR)
+	require.Equal(t, [][]string{{"example.com/a", "example.com/b"}}, graph.Cycles())
+
+	order, err := graph.Sort()
+	var cycleErr *workgraph.CycleError
+	require.True(t, errors.As(err, &cycleErr))
+	require.Equal(t, graph.Cycles(), cycleErr.Cycles)
+	require.Equal(t, [][]string{{"example.com/a", "example.com/b", "example.com/a"}}, cycleErr.Chains)
+	require.Equal(t, "dependency cycles: example.com/a -> example.com/b -> example.com/a", cycleErr.Error())
+	require.ElementsMatch(t, []string{"example.com/a", "example.com/b", "example.com/c"}, order)
+	require.Equal(t, "example.com/c", order[2])
+
+	_, err = graph.Levels()
+	require.ErrorAs(t, err, &cycleErr)
+}
+
+// TestGraph_Levels tests modules are grouped by the longest path to a leaf
+// TestGraph_Levels 测试模块按到叶子的最长路径分组
+func TestGraph_Levels(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "test-workgraph-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "app"), "example.com/app", "\nrequire (\n\texample.com/core v1.0.0\n\texample.com/lib v1.0.0\n)\n")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "core"), "example.com/core", "")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "lib"), "example.com/lib", "\nrequire example.com/core v1.0.0\n")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "tool"), "example.com/tool", "")
+
+	levels := rese.V1(scanGraph(t, tempDIR).Levels())
+	require.Equal(t, [][]string{
+		{"example.com/core", "example.com/tool"},
+		{"example.com/lib"},
+		{"example.com/app"},
+	}, levels)
+}
+
+// TestGraph_Chain tests the chain follows the edges of a longer cycle
+// TestGraph_Chain 测试边链沿较长环的边排列
+func TestGraph_Chain(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "test-workgraph-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "a"), "example.com/a", "\nrequire example.com/c v1.0.0\n")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "b"), "example.com/b", "\nrequire example.com/a v1.0.0\n")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "c"), "example.com/c", "\nrequire example.com/b v1.0.0\n")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "d"), "example.com/d", "\nrequire example.com/d v1.0.0\n")
+
+	_, err := scanGraph(t, tempDIR).Sort()
+	var cycleErr *workgraph.CycleError
+	require.ErrorAs(t, err, &cycleErr)
+	require.Equal(t, [][]string{
+		{"example.com/a", "example.com/c", "example.com/b", "example.com/a"},
+		{"example.com/d", "example.com/d"},
+	}, cycleErr.Chains)
+}
+
+// TestGraph_Render tests the DOT and Mermaid output
+// TestGraph_Render 测试 DOT 和 Mermaid 输出
+func TestGraph_Render(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "test-workgraph-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "a"), "example.com/a", "\nrequire (\n\texample.com/b v1.0.0\n\texample.com/c v1.0.0\n)\n\nreplace example.com/c => ../c\n")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "b"), "example.com/b", "")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "c"), "example.com/c", "")
+	graph := scanGraph(t, tempDIR)
+
+	var dot bytes.Buffer
+	require.NoError(t, graph.WriteDot(&dot))
+	require.Equal(t, `digraph workspace {
+	rankdir=LR;
+	"example.com/a";
+	"example.com/b";
+	"example.com/c";
+	"example.com/a" -> "example.com/b";
+	"example.com/a" -> "example.com/c" [style=dashed];
+}
+`, dot.String())
+
+	var mermaid bytes.Buffer
+	require.NoError(t, graph.WriteMermaid(&mermaid))
+	require.Equal(t, `graph LR
+    m0["example.com/a"]
+    m1["example.com/b"]
+    m2["example.com/c"]
+    m0 --> m1
+    m0 -.-> m2
+`, mermaid.String())
+}
+
+// TestGraph_Dependents tests changed files map to modules that expand to their dependents
+// TestGraph_Dependents 测试变更文件对应到模块，并扩展到其依赖方
+func TestGraph_Dependents(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "test-workgraph-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	testfs.WriteGoMod(t, tempDIR, "example.com/root", "")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "app"), "example.com/app", "\nrequire example.com/lib v1.0.0\n")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "lib"), "example.com/lib", "\nrequire example.com/core v1.0.0\n")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "lib", "core"), "example.com/core", "")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "libx"), "example.com/libx", "")
+	graph := scanGraph(t, tempDIR)
+
+	owner, ok := graph.Owner(filepath.Join(tempDIR, "lib", "core", "deleted.go"))
+	require.True(t, ok)
+	require.Equal(t, "example.com/core", owner)
+
+	owner, ok = graph.Owner(filepath.Join(tempDIR, "libx", "x.go"))
+	require.True(t, ok)
+	require.Equal(t, "example.com/libx", owner)
+
+	owner, ok = graph.Owner(filepath.Join(tempDIR, "README.md"))
+	require.True(t, ok)
+	require.Equal(t, "example.com/root", owner)
+
+	_, ok = graph.Owner(filepath.Join(filepath.Dir(tempDIR), "other.go"))
+	require.False(t, ok)
+
+	require.Equal(t, []string{"example.com/app", "example.com/lib", "example.com/core"}, graph.Dependents("example.com/core"))
+	require.Equal(t, []string{"example.com/libx"}, graph.Dependents("example.com/libx"))
+	require.Empty(t, graph.Dependents())
+}
@@ -0,0 +1,49 @@
+package workgraph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteDot renders the graph in Graphviz DOT, edges through local replaces are dashed
+// WriteDot 以 Graphviz DOT 呈现依赖图，经由本地 replace 的边为虚线
+func (g *Graph) WriteDot(w io.Writer) error {
+	out := bufio.NewWriter(w)
+	fmt.Fprintln(out, "digraph workspace {")
+	fmt.Fprintln(out, "\trankdir=LR;")
+	for _, module := range g.modules {
+		fmt.Fprintf(out, "\t%s;\n", strconv.Quote(module.ModulePath))
+	}
+	for _, edge := range g.Edges() {
+		style := ""
+		if edge.Replaced {
+			style = " [style=dashed]"
+		}
+		fmt.Fprintf(out, "\t%s -> %s%s;\n", strconv.Quote(edge.From), strconv.Quote(edge.To), style)
+	}
+	fmt.Fprintln(out, "}")
+	return out.Flush()
+}
+
+// WriteMermaid renders the graph as a Mermaid flowchart, edges through local replaces are dotted
+// Nodes get short ids since module paths contain characters Mermaid does not take in ids
+//
+// WriteMermaid 以 Mermaid 流程图呈现依赖图，经由本地 replace 的边为点线
+// 模块路径包含 Mermaid 不接受作为 id 的字符，因此节点使用短 id
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	out := bufio.NewWriter(w)
+	fmt.Fprintln(out, "graph LR")
+	for idx, module := range g.modules {
+		fmt.Fprintf(out, "    m%d[\"%s\"]\n", idx, module.ModulePath)
+	}
+	for _, edge := range g.Edges() {
+		arrow := "-->"
+		if edge.Replaced {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(out, "    m%d %s m%d\n", g.index[edge.From], arrow, g.index[edge.To])
+	}
+	return out.Flush()
+}
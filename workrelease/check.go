@@ -0,0 +1,225 @@
+package workrelease
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-mate/go-work/internal/gover"
+	"github.com/go-mate/go-work/workspath"
+	"github.com/yyle88/tern"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// Rule IDs reported by Check
+// Check 报告的规则 ID
+const (
+	RuleGoMod        = "go-mod"        // go.mod reads and parses, the other rules need it // go.mod 可读取并解析，其它规则依赖于它
+	RuleLocalReplace = "local-replace" // No filesystem replace directives // 没有文件系统 replace 指令
+	RulePseudoTagged = "pseudo-tagged" // No pseudo-versions older than a tag of the workspace module // 没有比工作区模块的标签更旧的伪版本
+	RuleGoSum        = "go-sum"        // go.sum holds every require // go.sum 包含每个 require
+	RuleGoVersion    = "go-version"    // Go directive within the policy // go 指令不超过策略版本
+)
+
+// Status is the outcome of one rule on one module
+// Status 是一条规则在一个模块上的结果
+type Status string
+
+const (
+	StatusPass Status = "pass" // Rule holds // 规则成立
+	StatusFail Status = "fail" // Rule is broken // 规则不成立
+	StatusSkip Status = "skip" // Rule does not apply // 规则不适用
+)
+
+// Result is the outcome of one rule on one module
+// Result 是一条规则在一个模块上的结果
+type Result struct {
+	Module string   `json:"module"`           // Module path // 模块路径
+	Dir    string   `json:"dir"`              // Module DIR // 模块 DIR
+	Rule   string   `json:"rule"`             // Rule ID // 规则 ID
+	Status Status   `json:"status"`           // Outcome // 结果
+	Issues []string `json:"issues,omitempty"` // What breaks the rule, or why it is skipped // 违反规则的内容，或跳过的原因
+}
+
+// CheckOptions configures Check
+// CheckOptions 配置 Check
+type CheckOptions struct {
+	Root  string   // Repo root the tag prefixes are relative to // 标签前缀所相对的仓库根
+	Tags  []string // Tags of the repo, nil skips the pseudo-tagged rule // 仓库的标签，为 nil 时跳过 pseudo-tagged 规则
+	MaxGo string   // Highest go directive allowed, blank skips the go-version rule // 允许的最高 go 指令，为空时跳过 go-version 规则
+}
+
+// Check runs the release rules over modules, returning one result per module and rule in that order
+// A module whose go.mod cannot be read or parsed fails the go-mod rule and gets no other results
+// DIRs without a go.mod are not modules and get no results at all
+//
+// Check 对 modules 运行发布规则，按模块和规则的顺序为每个模块的每条规则返回一个结果
+// go.mod 无法读取或解析的模块在 go-mod 规则上失败，且没有其它结果
+// 没有 go.mod 的 DIR 不是模块，不产生任何结果
+func Check(modules []workspath.Module, opts CheckOptions) []*Result {
+	tagged := map[string]latestTag{}
+	for _, module := range modules {
+		if module.ModulePath == "" || opts.Tags == nil {
+			continue
+		}
+		prefix := TagPrefix(opts.Root, module.Path, module.ModulePath)
+		if versions := Versions(opts.Tags, prefix, module.ModulePath); len(versions) > 0 {
+			version := versions[len(versions)-1]
+			tagged[module.ModulePath] = latestTag{tag: prefix + version, version: version}
+		}
+	}
+
+	var results []*Result
+	for _, module := range modules {
+		if module.Err == nil && module.File == nil {
+			continue
+		}
+		add := func(rule string, issues []string) {
+			results = append(results, &Result{Module: module.ModulePath, Dir: module.Path, Rule: rule, Status: tern.BVV(len(issues) > 0, StatusFail, StatusPass), Issues: issues})
+		}
+		skip := func(rule string, reason string) {
+			results = append(results, &Result{Module: module.ModulePath, Dir: module.Path, Rule: rule, Status: StatusSkip, Issues: []string{reason}})
+		}
+		if module.Err != nil {
+			add(RuleGoMod, []string{module.Err.Error()})
+			continue
+		}
+		add(RuleGoMod, nil)
+		add(RuleLocalReplace, checkLocalReplace(module))
+		if opts.Tags == nil {
+			skip(RulePseudoTagged, "no git tags")
+		} else {
+			add(RulePseudoTagged, checkPseudoTagged(module, tagged))
+		}
+		add(RuleGoSum, checkGoSum(module))
+		if opts.MaxGo == "" {
+			skip(RuleGoVersion, "no policy version")
+		} else {
+			add(RuleGoVersion, checkGoVersion(module, opts.MaxGo))
+		}
+	}
+	return results
+}
+
+// Failed reports whether any result is a failure
+// Failed 判断是否有结果失败
+func Failed(results []*Result) bool {
+	for _, res := range results {
+		if res.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// checkLocalReplace lists the filesystem replaces, go ignores those once the module is a dependency
+// checkLocalReplace 列出文件系统 replace，模块作为依赖时 go 会忽略它们
+func checkLocalReplace(mod workspath.Module) []string {
+	var issues []string
+	for _, rep := range mod.Replace {
+		if modfile.IsDirectoryPath(rep.New.Path) {
+			issues = append(issues, rep.Old.Path+" => "+rep.New.Path)
+		}
+	}
+	return issues
+}
+
+// latestTag is the highest tag of a workspace module
+// latestTag 是工作区模块的最高标签
+type latestTag struct {
+	tag     string // Tag name with prefix // 带前缀的标签名称
+	version string // Version part of the tag // 标签的版本部分
+}
+
+// checkPseudoTagged lists requires of workspace modules at pseudo-versions older than the latest tag of the module
+// A pseudo-version sorts above its base tag, so one pointing at commits after the latest tag passes
+//
+// checkPseudoTagged 列出以比模块最新标签更旧的伪版本依赖工作区模块的 require
+// 伪版本排在其基础标签之后，因此指向最新标签之后提交的伪版本可以通过
+func checkPseudoTagged(mod workspath.Module, tagged map[string]latestTag) []string {
+	var issues []string
+	for _, req := range mod.Require {
+		latest, ok := tagged[req.Path]
+		if ok && module.IsPseudoVersion(req.Version) && semver.Compare(latest.version, req.Version) > 0 {
+			issues = append(issues, fmt.Sprintf("%s %s is older than %s", req.Path, req.Version, latest.tag))
+		}
+	}
+	return issues
+}
+
+// checkGoSum lists requires without a go.mod hash in go.sum, a missing go.sum counts when anything is required
+// A require replaced by another module is looked up as the replacement, which is what go.sum holds
+// Requires satisfied by a filesystem replace have no go.sum line and are left to the local-replace rule
+//
+// checkGoSum 列出在 go.sum 中没有 go.mod 哈希的 require，有依赖时缺少 go.sum 也计为问题
+// 被其它模块替换的 require 按替换目标查找，go.sum 中保存的正是替换目标
+// 由文件系统 replace 满足的 require 没有 go.sum 行，交给 local-replace 规则处理
+func checkGoSum(mod workspath.Module) []string {
+	var requires []module.Version
+	for _, req := range mod.Require {
+		rep, ok := replacement(mod, req)
+		switch {
+		case !ok:
+			requires = append(requires, module.Version{Path: req.Path, Version: req.Version})
+		case !modfile.IsDirectoryPath(rep.New.Path):
+			requires = append(requires, rep.New)
+		}
+	}
+	if len(requires) == 0 {
+		return nil
+	}
+	content, err := os.ReadFile(filepath.Join(mod.Path, "go.sum"))
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{"go.sum missing"}
+	} else if err != nil {
+		return []string{err.Error()}
+	}
+	sums := map[string]bool{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) == 3 {
+			sums[fields[0]+" "+strings.TrimSuffix(fields[1], "/go.mod")] = true
+		}
+	}
+	var issues []string
+	for _, req := range requires {
+		if !sums[req.Path+" "+req.Version] {
+			issues = append(issues, "no go.sum entry for "+req.Path+" "+req.Version)
+		}
+	}
+	return issues
+}
+
+// replacement returns the replace applying to req, one for its own version before one for every version, like go does
+// replacement 返回适用于 req 的 replace，与 go 一样，针对其版本的优先于针对所有版本的
+func replacement(mod workspath.Module, req workspath.Require) (workspath.Replace, bool) {
+	var found workspath.Replace
+	var ok bool
+	for _, rep := range mod.Replace {
+		if rep.Old.Path != req.Path {
+			continue
+		}
+		if rep.Old.Version == req.Version {
+			return rep, true
+		}
+		if rep.Old.Version == "" {
+			found, ok = rep, true
+		}
+	}
+	return found, ok
+}
+
+// checkGoVersion reports a go directive above maxGo
+// checkGoVersion 报告高于 maxGo 的 go 指令
+func checkGoVersion(mod workspath.Module, maxGo string) []string {
+	if mod.GoVersion != "" && gover.Compare(mod.GoVersion, maxGo) > 0 {
+		return []string{"go " + mod.GoVersion + " is above " + maxGo}
+	}
+	return nil
+}
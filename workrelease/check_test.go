@@ -0,0 +1,97 @@
+package workrelease_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-mate/go-work/internal/testfs"
+	"github.com/go-mate/go-work/workrelease"
+	"github.com/go-mate/go-work/workspath"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+	"golang.org/x/mod/module"
+)
+
+// TestCheck tests each rule passes on a clean module and fails on a broken one
+// TestCheck 测试每条规则在正常模块上通过、在有问题的模块上失败
+func TestCheck(t *testing.T) {
+	tempDIR := t.TempDir()
+	testfs.WriteFile(t, filepath.Join(tempDIR, "api", "go.mod"), "module example.com/repo/api\n\ngo 1.23.1\n\nrequire (\n\texample.com/repo/lib v0.0.0-20240101000000-abcdefabcdef\n\tgithub.com/stretchr/testify v1.9.0\n\tgo.uber.org/zap v1.27.0\n)\n\nreplace example.com/repo/lib => ../lib\n")
+	testfs.WriteFile(t, filepath.Join(tempDIR, "api", "go.sum"), "go.uber.org/zap v1.27.0 h1:abc=\ngo.uber.org/zap v1.27.0/go.mod h1:def=\n")
+	testfs.WriteFile(t, filepath.Join(tempDIR, "lib", "go.mod"), "module example.com/repo/lib\n\ngo 1.22\n\nrequire go.uber.org/zap v1.27.0\n")
+	modules := rese.V1(workspath.ScanModules(tempDIR, workspath.ScanDeep()))
+
+	results := workrelease.Check(modules, workrelease.CheckOptions{Root: tempDIR, Tags: []string{"lib/v0.1.0"}, MaxGo: "1.22"})
+	require.True(t, workrelease.Failed(results))
+	require.Len(t, results, 10)
+
+	status := map[string]workrelease.Status{}
+	for _, res := range results {
+		status[filepath.Base(res.Dir)+" "+res.Rule] = res.Status
+	}
+	require.Equal(t, map[string]workrelease.Status{
+		"api go-mod":        workrelease.StatusPass,
+		"api local-replace": workrelease.StatusFail,
+		"api pseudo-tagged": workrelease.StatusFail,
+		"api go-sum":        workrelease.StatusFail,
+		"api go-version":    workrelease.StatusFail,
+		"lib go-mod":        workrelease.StatusPass,
+		"lib local-replace": workrelease.StatusPass,
+		"lib pseudo-tagged": workrelease.StatusPass,
+		"lib go-sum":        workrelease.StatusFail,
+		"lib go-version":    workrelease.StatusPass,
+	}, status)
+	require.Equal(t, []string{"example.com/repo/lib v0.0.0-20240101000000-abcdefabcdef is older than lib/v0.1.0"}, results[2].Issues)
+	require.Equal(t, []string{"no go.sum entry for github.com/stretchr/testify v1.9.0"}, results[3].Issues)
+	require.Equal(t, []string{"go.sum missing"}, results[8].Issues)
+
+	// A pseudo-version after the latest tag is the usual reason to use one
+	// 位于最新标签之后的伪版本正是使用伪版本的常见原因
+	api := modules[0]
+	api.Require = []workspath.Require{{Path: "example.com/repo/lib", Version: "v0.1.1-0.20240101000000-abcdefabcdef"}}
+	results = workrelease.Check([]workspath.Module{api, modules[1]}, workrelease.CheckOptions{Root: tempDIR, Tags: []string{"lib/v0.1.0"}})
+	require.Equal(t, workrelease.RulePseudoTagged, results[2].Rule)
+	require.Equal(t, workrelease.StatusPass, results[2].Status)
+	results = workrelease.Check([]workspath.Module{api, modules[1]}, workrelease.CheckOptions{Root: tempDIR, Tags: []string{"lib/v0.1.0", "lib/v0.1.1"}})
+	require.Equal(t, workrelease.StatusFail, results[2].Status)
+
+	// A require satisfied by a filesystem replace needs no go.sum line
+	// 由文件系统 replace 满足的 require 不需要 go.sum 行
+	testfs.WriteFile(t, filepath.Join(tempDIR, "api", "go.sum"), "github.com/stretchr/testify v1.9.0/go.mod h1:ghi=\ngo.uber.org/zap v1.27.0/go.mod h1:def=\n")
+	results = workrelease.Check(modules[:1], workrelease.CheckOptions{Root: tempDIR})
+	require.Equal(t, workrelease.RuleLocalReplace, results[1].Rule)
+	require.Equal(t, workrelease.StatusFail, results[1].Status)
+	require.Equal(t, workrelease.RuleGoSum, results[3].Rule)
+	require.Equal(t, workrelease.StatusPass, results[3].Status)
+
+	// A require replaced by another module is looked up as the replacement
+	// 被其它模块替换的 require 按替换目标查找
+	fork := modules[0]
+	fork.Require = []workspath.Require{{Path: "go.uber.org/zap", Version: "v1.27.0"}}
+	fork.Replace = []workspath.Replace{{Old: module.Version{Path: "go.uber.org/zap"}, New: module.Version{Path: "github.com/fork/zap", Version: "v1.27.1"}}}
+	results = workrelease.Check([]workspath.Module{fork}, workrelease.CheckOptions{Root: tempDIR})
+	require.Equal(t, []string{"no go.sum entry for github.com/fork/zap v1.27.1"}, results[3].Issues)
+	testfs.WriteFile(t, filepath.Join(tempDIR, "api", "go.sum"), "github.com/fork/zap v1.27.1/go.mod h1:jkl=\n")
+	results = workrelease.Check([]workspath.Module{fork}, workrelease.CheckOptions{Root: tempDIR})
+	require.Equal(t, workrelease.StatusPass, results[3].Status)
+
+	results = workrelease.Check(modules[1:], workrelease.CheckOptions{Root: tempDIR})
+	require.Equal(t, workrelease.StatusSkip, results[2].Status)
+	require.Equal(t, workrelease.StatusSkip, results[4].Status)
+}
+
+// TestCheck_InvalidGoMod tests a go.mod that does not parse fails the go-mod rule, so the preflight fails
+// TestCheck_InvalidGoMod 测试无法解析的 go.mod 在 go-mod 规则上失败，从而使预检失败
+func TestCheck_InvalidGoMod(t *testing.T) {
+	tempDIR := t.TempDir()
+	testfs.WriteFile(t, filepath.Join(tempDIR, "bad", "go.mod"), "module example.com/bad\n\nrequire\n")
+	modules := rese.V1(workspath.ScanModules(tempDIR, workspath.ScanDeep()))
+	require.Len(t, modules, 1)
+
+	results := workrelease.Check(modules, workrelease.CheckOptions{Root: tempDIR})
+	require.True(t, workrelease.Failed(results))
+	require.Len(t, results, 1)
+	require.Equal(t, workrelease.RuleGoMod, results[0].Rule)
+	require.Equal(t, workrelease.StatusFail, results[0].Status)
+	require.Len(t, results[0].Issues, 1)
+}
@@ -0,0 +1,191 @@
+package workrelease
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-mate/go-work/workspath"
+	"golang.org/x/mod/module"
+)
+
+// Major version layouts of a module
+// 模块的主版本布局
+const (
+	LayoutNone   = ""       // Module path without major suffix // 模块路径没有主版本后缀
+	LayoutSubdir = "subdir" // Module path /vN kept in a vN DIR // 模块路径 /vN 位于 vN DIR 中
+	LayoutBranch = "branch" // Module path /vN kept in the DIR of earlier majors // 模块路径 /vN 位于早期主版本的 DIR 中
+)
+
+// SIVResult is the semantic import versioning report of one module
+// SIVResult 是一个模块的语义导入版本检查报告
+type SIVResult struct {
+	Module string   `json:"module"`           // Module path // 模块路径
+	Dir    string   `json:"dir"`              // Module DIR // 模块 DIR
+	Major  string   `json:"major"`            // Major version of the module path, like v1 or v2 // 模块路径的主版本，如 v1 或 v2
+	Layout string   `json:"layout"`           // Major version layout, blank below v2 // 主版本布局，v2 以下为空
+	Latest string   `json:"latest"`           // Highest tag of the module, blank when untagged // 模块的最高标签，未打标签时为空
+	Issues []string `json:"issues,omitempty"` // Problems found // 发现的问题
+}
+
+// majorDIRRE matches DIR names of major versions like v2
+// majorDIRRE 匹配 v2 这类主版本 DIR 名称
+var majorDIRRE = regexp.MustCompile(`^v([2-9]|[1-9][0-9]+)$`)
+
+// CheckSIV checks semantic import versioning of modules in the repo at root with tags
+// Compares the module path suffix with the DIR layout and the tags, finds module paths declared twice,
+// and reports imports of workspace modules at a major version no workspace module has
+// Imports are read through workspath.ScanPackages with opts, like WithExclude or RespectGitignore
+//
+// CheckSIV 检查 root 仓库中 modules 的语义导入版本，使用 tags 作为标签
+// 比较模块路径后缀与 DIR 布局和标签，查找重复声明的模块路径，
+// 并报告导入了工作区中不存在的主版本的工作区模块的导入
+// 导入通过 workspath.ScanPackages 按 opts 读取，如 WithExclude 或 RespectGitignore
+func CheckSIV(modules []workspath.Module, root string, tags []string, opts ...workspath.Option) []*SIVResult {
+	dirs := map[string][]string{}
+	for _, mod := range modules {
+		if mod.ModulePath != "" {
+			dirs[mod.ModulePath] = append(dirs[mod.ModulePath], mod.Path)
+		}
+	}
+
+	var results []*SIVResult
+	for _, mod := range modules {
+		if mod.ModulePath == "" {
+			continue
+		}
+		res := &SIVResult{Module: mod.ModulePath, Dir: mod.Path}
+		results = append(results, res)
+
+		_, pathMajor, ok := module.SplitPathVersion(mod.ModulePath)
+		if !ok {
+			res.Issues = append(res.Issues, "module path has an invalid major version suffix")
+			continue
+		}
+		res.Major = fmt.Sprintf("v%d", max(majorNumber(pathMajor), 1))
+		res.Layout = layoutOf(mod.Path, pathMajor)
+
+		base := filepath.Base(mod.Path)
+		if majorDIRRE.MatchString(base) && res.Layout != LayoutSubdir && mod.Path != root {
+			res.Issues = append(res.Issues, fmt.Sprintf("DIR %s holds module path without suffix /%s", base, base))
+		}
+		for _, dir := range dirs[mod.ModulePath] {
+			if dir != mod.Path {
+				res.Issues = append(res.Issues, "module path also declared in "+dir)
+			}
+		}
+
+		prefix := TagPrefix(root, mod.Path, mod.ModulePath)
+		if versions := Versions(tags, prefix, mod.ModulePath); len(versions) > 0 {
+			res.Latest = prefix + versions[len(versions)-1]
+		}
+		res.Issues = append(res.Issues, higherMajors(tags, prefix, pathMajor)...)
+		res.Issues = append(res.Issues, wrongMajorImports(mod, modules, opts)...)
+	}
+	return results
+}
+
+// layoutOf returns the major version layout of a module at dir with pathMajor
+// layoutOf 返回位于 dir、主版本路径为 pathMajor 的模块的主版本布局
+func layoutOf(dir string, pathMajor string) string {
+	if !strings.HasPrefix(pathMajor, "/") {
+		return LayoutNone
+	}
+	if filepath.Base(dir) == pathMajor[1:] {
+		return LayoutSubdir
+	}
+	return LayoutBranch
+}
+
+// wrongMajorImports lists imports in the packages of mod that match a workspace module path
+// but at a major version no workspace module provides
+//
+// wrongMajorImports 列出 mod 的包中与工作区模块路径匹配、
+// 但主版本在工作区模块中不存在的导入
+func wrongMajorImports(mod workspath.Module, modules []workspath.Module, opts []workspath.Option) []string {
+	imports, err := moduleImports(mod, opts)
+	var issues []string
+	var scanErrs workspath.ScanErrors
+	if errors.As(err, &scanErrs) {
+		for _, scanErr := range scanErrs {
+			issues = append(issues, scanErr.Error())
+		}
+	} else if err != nil {
+		return []string{err.Error()}
+	}
+	for _, path := range imports {
+		var matched []string
+		var bestBase string
+		for _, other := range modules {
+			base, pathMajor, ok := module.SplitPathVersion(other.ModulePath)
+			if !ok || other.ModulePath == "" || strings.HasPrefix(pathMajor, ".") {
+				continue
+			}
+			if path != base && !strings.HasPrefix(path, base+"/") {
+				continue
+			}
+			if len(base) > len(bestBase) {
+				bestBase, matched = base, nil
+			}
+			if base == bestBase {
+				matched = append(matched, other.ModulePath)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		major := importMajor(strings.TrimPrefix(path, bestBase))
+		found := false
+		for _, modulePath := range matched {
+			_, pathMajor, _ := module.SplitPathVersion(modulePath)
+			if max(majorNumber(pathMajor), 1) == major {
+				found = true
+			}
+		}
+		if !found {
+			issues = append(issues, fmt.Sprintf("import %s is major v%d but the workspace has %s", path, major, strings.Join(matched, ", ")))
+		}
+	}
+	return issues
+}
+
+// importMajor returns the major version of an import from the part after the module base path
+// "/v2/pkg" gives 2, "/pkg" or "" gives 1
+//
+// importMajor 根据模块基础路径之后的部分返回导入的主版本
+// "/v2/pkg" 返回 2，"/pkg" 或 "" 返回 1
+func importMajor(rest string) int {
+	elem, _, _ := strings.Cut(strings.TrimPrefix(rest, "/"), "/")
+	if !majorDIRRE.MatchString(elem) {
+		return 1
+	}
+	n, _ := strconv.Atoi(elem[1:])
+	return n
+}
+
+// moduleImports returns the sorted distinct imports of the packages of mod, tests included
+// Packages are listed by workspath.ScanPackages with opts, so nested modules and skipped paths match the scans
+//
+// moduleImports 返回 mod 中各包去重并排序后的导入，包括测试
+// 包由 workspath.ScanPackages 按 opts 列出，因此嵌套模块和跳过的路径与扫描保持一致
+func moduleImports(mod workspath.Module, opts []workspath.Option) ([]string, error) {
+	packages, err := workspath.ScanPackages(mod, opts...)
+	seen := map[string]bool{}
+	for _, pkg := range packages {
+		for _, paths := range [][]string{pkg.Imports, pkg.TestImports} {
+			for _, path := range paths {
+				seen[path] = true
+			}
+		}
+	}
+	imports := make([]string, 0, len(seen))
+	for path := range seen {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	return imports, err
+}
@@ -0,0 +1,75 @@
+package workrelease_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-mate/go-work/internal/testfs"
+	"github.com/go-mate/go-work/workrelease"
+	"github.com/go-mate/go-work/workspath"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+// TestCheckSIV tests layouts, tag majors, duplicate module paths and imports at a wrong major
+// TestCheckSIV 测试布局、标签主版本、重复的模块路径以及错误主版本的导入
+func TestCheckSIV(t *testing.T) {
+	tempDIR := t.TempDir()
+	testfs.WriteFile(t, filepath.Join(tempDIR, "go.mod"), "module example.com/repo\n\ngo 1.22\n")
+	testfs.WriteFile(t, filepath.Join(tempDIR, "main.go"), "package main\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/repo/libs/core\"\n\t\"example.com/repo/svc/v2/api\"\n)\n")
+	testfs.WriteFile(t, filepath.Join(tempDIR, "libs", "core", "v2", "go.mod"), "module example.com/repo/libs/core/v2\n\ngo 1.22\n")
+	testfs.WriteFile(t, filepath.Join(tempDIR, "libs", "core", "v2", "core.go"), "package core\n")
+	testfs.WriteFile(t, filepath.Join(tempDIR, "svc", "go.mod"), "module example.com/repo/svc/v2\n\ngo 1.22\n")
+	testfs.WriteFile(t, filepath.Join(tempDIR, "svc", "api", "api.go"), "package api\n\nimport _ \"example.com/repo/libs/core/v2\"\n")
+	testfs.WriteFile(t, filepath.Join(tempDIR, "old", "v3", "go.mod"), "module example.com/repo/old\n\ngo 1.22\n")
+	testfs.WriteFile(t, filepath.Join(tempDIR, "old", "v3", "old.go"), "package old\n")
+	testfs.WriteFile(t, filepath.Join(tempDIR, "copy", "go.mod"), "module example.com/repo/old\n\ngo 1.22\n")
+	testfs.WriteFile(t, filepath.Join(tempDIR, "copy", "copy.go"), "package old\n")
+	testfs.WriteFile(t, filepath.Join(tempDIR, "gen", "gen.go"), "package gen\n\nimport _ \"example.com/repo/svc/api\"\n")
+	testfs.WriteFile(t, filepath.Join(tempDIR, "_old", "old.go"), "package old\n\nimport _ \"example.com/repo/svc/api\"\n")
+	modules := rese.V1(workspath.ScanModules(tempDIR, workspath.ScanDeep()))
+	require.Len(t, modules, 5)
+
+	tags := []string{"v1.0.0", "svc/v2.1.0", "svc/v3.0.0", "libs/core/v2.0.0"}
+	results := workrelease.CheckSIV(modules, tempDIR, tags, workspath.WithExclude("gen"))
+	require.Len(t, results, 5)
+	byDIR := map[string]*workrelease.SIVResult{}
+	for _, res := range results {
+		byDIR[rese.C1(filepath.Rel(tempDIR, res.Dir))] = res
+	}
+
+	require.Equal(t, &workrelease.SIVResult{
+		Module: "example.com/repo",
+		Dir:    tempDIR,
+		Major:  "v1",
+		Layout: workrelease.LayoutNone,
+		Latest: "v1.0.0",
+		Issues: []string{"import example.com/repo/libs/core is major v1 but the workspace has example.com/repo/libs/core/v2"},
+	}, byDIR["."])
+
+	core := byDIR[filepath.Join("libs", "core", "v2")]
+	require.Equal(t, "v2", core.Major)
+	require.Equal(t, workrelease.LayoutSubdir, core.Layout)
+	require.Equal(t, "libs/core/v2.0.0", core.Latest)
+	require.Empty(t, core.Issues)
+
+	svc := byDIR["svc"]
+	require.Equal(t, workrelease.LayoutBranch, svc.Layout)
+	require.Equal(t, "svc/v2.1.0", svc.Latest)
+	require.Equal(t, []string{"tag svc/v3.0.0 needs module path suffix /v3"}, svc.Issues)
+
+	require.Equal(t, []string{"module path also declared in " + filepath.Join(tempDIR, "old", "v3")}, byDIR["copy"].Issues)
+	require.Equal(t, []string{
+		"DIR v3 holds module path without suffix /v3",
+		"module path also declared in " + filepath.Join(tempDIR, "copy"),
+	}, byDIR[filepath.Join("old", "v3")].Issues)
+
+	// Imports follow the scan options, gen is read once no longer excluded while _old stays skipped
+	// 导入遵循扫描选项，gen 不再被排除后会被读取，而 _old 仍被跳过
+	results = workrelease.CheckSIV(modules, tempDIR, tags)
+	require.Equal(t, "example.com/repo", results[0].Module)
+	require.Equal(t, []string{
+		"import example.com/repo/libs/core is major v1 but the workspace has example.com/repo/libs/core/v2",
+		"import example.com/repo/svc/api is major v1 but the workspace has example.com/repo/svc/v2",
+	}, results[0].Issues)
+}
@@ -0,0 +1,183 @@
+// Package workrelease: Release checks and tag names for the modules of a multi-module repo
+// Nested modules are tagged with their DIR relative to the repo root as prefix, like "libs/core/v1.2.0"
+//
+// workrelease: 多模块仓库中各模块的发布检查和标签名称
+// 嵌套模块以其相对仓库根的 DIR 作为标签前缀，例如 "libs/core/v1.2.0"
+package workrelease
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-mate/go-work/workspath"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// TagPrefix returns the tag prefix of the module at dir with modulePath, inside the repo at root
+// A trailing major DIR like "v2" matching the module path is dropped, as the go command expects
+//
+// TagPrefix 返回位于 root 仓库内 dir 处、模块路径为 modulePath 的模块的标签前缀
+// 与模块路径匹配的末尾主版本 DIR（如 "v2"）会被去掉，与 go 命令的要求一致
+func TagPrefix(root string, dir string, modulePath string) string {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == "." {
+		return ""
+	}
+	rel = filepath.ToSlash(rel)
+	if _, pathMajor, ok := module.SplitPathVersion(modulePath); ok && strings.HasPrefix(pathMajor, "/") {
+		if rel == pathMajor[1:] {
+			return ""
+		}
+		rel = strings.TrimSuffix(rel, pathMajor)
+	}
+	return rel + "/"
+}
+
+// Versions returns the versions tagged with prefix that suit modulePath, lowest first
+// Versions must be canonical semver with a major version matching the module path suffix
+//
+// Versions 返回以 prefix 为前缀且适用于 modulePath 的版本，从低到高
+// 版本必须为规范的 semver，且主版本与模块路径后缀匹配
+func Versions(tags []string, prefix string, modulePath string) []string {
+	_, pathMajor, ok := module.SplitPathVersion(modulePath)
+	if !ok {
+		return nil
+	}
+	var versions []string
+	for _, tag := range tags {
+		version, ok := strings.CutPrefix(tag, prefix)
+		if !ok || semver.Canonical(version) != version || version == "" {
+			continue
+		}
+		if module.CheckPathMajor(version, pathMajor) != nil {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	semver.Sort(versions)
+	return versions
+}
+
+// Bump is the part of the version raised by PlanTags
+// Bump 是 PlanTags 提升的版本部分
+type Bump string
+
+const (
+	BumpPatch Bump = "patch" // v1.2.3 => v1.2.4 // v1.2.3 => v1.2.4
+	BumpMinor Bump = "minor" // v1.2.3 => v1.3.0 // v1.2.3 => v1.3.0
+	BumpMajor Bump = "major" // v0.2.3 => v1.0.0, higher majors need a new module path // v0.2.3 => v1.0.0，更高的主版本需要新的模块路径
+)
+
+// TagPlan is the next release tag of one module
+// TagPlan 是一个模块的下一个发布标签
+type TagPlan struct {
+	Module string   `json:"module"`           // Module path // 模块路径
+	Dir    string   `json:"dir"`              // Module DIR // 模块 DIR
+	Prefix string   `json:"prefix"`           // Tag prefix, blank at the repo root // 标签前缀，在仓库根时为空
+	Latest string   `json:"latest"`           // Highest existing tag of the module, blank when untagged // 模块已有的最高标签，未打标签时为空
+	Next   string   `json:"next"`             // Proposed tag, blank when it cannot be computed // 建议的标签，无法计算时为空
+	Issues []string `json:"issues,omitempty"` // Major version problems // 主版本问题
+}
+
+// PlanTags proposes the next tag of each module with a module path, raising bump of its highest tag
+// Untagged modules start at v0.1.0, or at vN.0.0 when the module path ends in /vN
+// Tags with a major version above the module path suffix are reported as issues
+//
+// PlanTags 为每个有模块路径的模块建议下一个标签，提升其最高标签的 bump 部分
+// 未打标签的模块从 v0.1.0 开始，模块路径以 /vN 结尾时从 vN.0.0 开始
+// 主版本高于模块路径后缀的标签被报告为问题
+func PlanTags(modules []workspath.Module, root string, tags []string, bump Bump) []*TagPlan {
+	var plans []*TagPlan
+	for _, mod := range modules {
+		if mod.ModulePath == "" {
+			continue
+		}
+		plan := &TagPlan{Module: mod.ModulePath, Dir: mod.Path, Prefix: TagPrefix(root, mod.Path, mod.ModulePath)}
+		plans = append(plans, plan)
+
+		_, pathMajor, ok := module.SplitPathVersion(mod.ModulePath)
+		if !ok {
+			plan.Issues = append(plan.Issues, "module path has an invalid major version suffix")
+			continue
+		}
+		plan.Issues = append(plan.Issues, higherMajors(tags, plan.Prefix, pathMajor)...)
+
+		latest := ""
+		if versions := Versions(tags, plan.Prefix, mod.ModulePath); len(versions) > 0 {
+			latest = versions[len(versions)-1]
+			plan.Latest = plan.Prefix + latest
+		}
+		next, err := nextVersion(latest, pathMajor, bump)
+		if err != nil {
+			plan.Issues = append(plan.Issues, err.Error())
+			continue
+		}
+		plan.Next = plan.Prefix + next
+	}
+	return plans
+}
+
+// higherMajors reports tags with prefix whose major version is above the one the module path allows
+// higherMajors 报告以 prefix 为前缀、主版本高于模块路径所允许主版本的标签
+func higherMajors(tags []string, prefix string, pathMajor string) []string {
+	allowed := majorNumber(pathMajor)
+	var issues []string
+	for _, tag := range tags {
+		version, ok := strings.CutPrefix(tag, prefix)
+		if !ok || semver.Canonical(version) != version || version == "" || semver.Build(version) != "" {
+			continue
+		}
+		if major := majorNumber(semver.Major(version)); major > allowed && major >= 2 {
+			issues = append(issues, fmt.Sprintf("tag %s needs module path suffix /v%d", tag, major))
+		}
+	}
+	return issues
+}
+
+// nextVersion raises bump of latest, or returns the first version of the path major when latest is blank
+// A patch bump of a pre-release drops the pre-release, v1.3.0-rc.1 => v1.3.0
+//
+// nextVersion 提升 latest 的 bump 部分，latest 为空时返回该主版本路径的第一个版本
+// 对预发布版本做 patch 提升时去掉预发布部分，v1.3.0-rc.1 => v1.3.0
+func nextVersion(latest string, pathMajor string, bump Bump) (string, error) {
+	if latest == "" {
+		if major := majorNumber(pathMajor); major >= 2 {
+			return fmt.Sprintf("v%d.0.0", major), nil
+		}
+		return "v0.1.0", nil
+	}
+	base := strings.TrimSuffix(latest, semver.Prerelease(latest))
+	var major, minor, patch int
+	if _, err := fmt.Sscanf(base, "v%d.%d.%d", &major, &minor, &patch); err != nil {
+		return "", err
+	}
+	switch bump {
+	case BumpPatch:
+		if base == latest {
+			patch++
+		}
+	case BumpMinor:
+		minor, patch = minor+1, 0
+	case BumpMajor:
+		if major >= 1 {
+			return "", fmt.Errorf("major bump of %s needs module path suffix /v%d", latest, major+1)
+		}
+		major, minor, patch = 1, 0, 0
+	default:
+		return "", fmt.Errorf("unknown bump %q, expect patch, minor or major", bump)
+	}
+	return fmt.Sprintf("v%d.%d.%d", major, minor, patch), nil
+}
+
+// majorNumber returns N of a major like "v2", "/v2" or ".v2", zero when there is none
+// majorNumber 返回 "v2"、"/v2" 或 ".v2" 这类主版本中的 N，没有时返回零
+func majorNumber(major string) int {
+	n, err := strconv.Atoi(strings.TrimLeft(major, "/.v"))
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,65 @@
+package workrelease_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-mate/go-work/workrelease"
+	"github.com/go-mate/go-work/workspath"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTagPrefix tests nested DIRs become prefixes and major DIRs are dropped
+// TestTagPrefix 测试嵌套 DIR 成为前缀且主版本 DIR 被去掉
+func TestTagPrefix(t *testing.T) {
+	root := filepath.FromSlash("/repo")
+	require.Equal(t, "", workrelease.TagPrefix(root, root, "example.com/repo"))
+	require.Equal(t, "services/billing/", workrelease.TagPrefix(root, filepath.Join(root, "services", "billing"), "example.com/repo/services/billing"))
+	require.Equal(t, "services/billing/", workrelease.TagPrefix(root, filepath.Join(root, "services", "billing", "v2"), "example.com/repo/services/billing/v2"))
+	require.Equal(t, "", workrelease.TagPrefix(root, filepath.Join(root, "v3"), "example.com/repo/v3"))
+	require.Equal(t, "services/billing/", workrelease.TagPrefix(root, filepath.Join(root, "services", "billing"), "example.com/repo/services/billing/v2"))
+}
+
+// TestVersions tests tags are filtered by prefix and major version
+// TestVersions 测试标签按前缀和主版本过滤
+func TestVersions(t *testing.T) {
+	tags := []string{"v1.0.0", "v1.2.0", "v2.0.0", "libs/core/v0.3.0", "libs/core/v0.10.0", "libs/core/v1.0", "nightly"}
+	require.Equal(t, []string{"v1.0.0", "v1.2.0"}, workrelease.Versions(tags, "", "example.com/repo"))
+	require.Equal(t, []string{"v2.0.0"}, workrelease.Versions(tags, "", "example.com/repo/v2"))
+	require.Equal(t, []string{"v0.3.0", "v0.10.0"}, workrelease.Versions(tags, "libs/core/", "example.com/repo/libs/core"))
+	require.Empty(t, workrelease.Versions(tags, "libs/other/", "example.com/repo/libs/other"))
+}
+
+// TestPlanTags tests next tags per module and major version issues
+// TestPlanTags 测试每个模块的下一个标签和主版本问题
+func TestPlanTags(t *testing.T) {
+	root := filepath.FromSlash("/repo")
+	modules := []workspath.Module{
+		{Path: root, ModulePath: "example.com/repo"},
+		{Path: filepath.Join(root, "services", "billing"), ModulePath: "example.com/repo/services/billing"},
+		{Path: filepath.Join(root, "services", "billing", "v2"), ModulePath: "example.com/repo/services/billing/v2"},
+		{Path: filepath.Join(root, "libs", "core"), ModulePath: "example.com/repo/libs/core"},
+		{Path: filepath.Join(root, "tools"), ModulePath: "example.com/repo/tools"},
+		{Path: filepath.Join(root, "scratch")},
+	}
+	tags := []string{"v1.0.0", "v1.1.0-rc.1", "services/billing/v1.4.0", "libs/core/v0.3.0", "libs/core/v2.0.0"}
+
+	plans := workrelease.PlanTags(modules, root, tags, workrelease.BumpPatch)
+	require.Len(t, plans, 5)
+	require.Equal(t, &workrelease.TagPlan{Module: "example.com/repo", Dir: root, Prefix: "", Latest: "v1.1.0-rc.1", Next: "v1.1.0"}, plans[0])
+	require.Equal(t, "services/billing/v1.4.1", plans[1].Next)
+	require.Equal(t, "services/billing/", plans[2].Prefix)
+	require.Equal(t, "", plans[2].Latest)
+	require.Equal(t, "services/billing/v2.0.0", plans[2].Next)
+	require.Equal(t, "libs/core/v0.3.1", plans[3].Next)
+	require.Equal(t, []string{"tag libs/core/v2.0.0 needs module path suffix /v2"}, plans[3].Issues)
+	require.Equal(t, "tools/v0.1.0", plans[4].Next)
+
+	plans = workrelease.PlanTags(modules, root, tags, workrelease.BumpMinor)
+	require.Equal(t, "services/billing/v1.5.0", plans[1].Next)
+
+	plans = workrelease.PlanTags(modules, root, tags, workrelease.BumpMajor)
+	require.Equal(t, "", plans[1].Next)
+	require.Equal(t, []string{"major bump of v1.4.0 needs module path suffix /v2"}, plans[1].Issues)
+	require.Equal(t, "libs/core/v1.0.0", plans[3].Next)
+}
@@ -0,0 +1,67 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/go-mate/go-work/workspath"
+	"github.com/yyle88/osexistpath/osomitexist"
+)
+
+// DiffEntry is one difference between the modules on disk and the go.work use directives
+// DiffEntry 是磁盘上的模块与 go.work use 指令之间的一处差异
+type DiffEntry struct {
+	Dir    string `json:"dir"`              // Absolute module DIR // 模块的绝对 DIR
+	Use    string `json:"use,omitempty"`    // Use path as written in go.work // go.work 中书写的 use 路径
+	Module string `json:"module,omitempty"` // Module path declared in go.mod // go.mod 中声明的模块路径
+}
+
+// DiffResult lists the drift between the modules on disk and the go.work use directives
+// DiffResult 列出磁盘上的模块与 go.work use 指令之间的偏差
+type DiffResult struct {
+	Added   []DiffEntry `json:"added"`   // Modules on disk without a use directive // 磁盘上没有 use 指令的模块
+	Missing []DiffEntry `json:"missing"` // Use directives pointing at DIRs that do not exist // 指向不存在 DIR 的 use 指令
+	Stale   []DiffEntry `json:"stale"`   // Use directives pointing at DIRs without go.mod // 指向没有 go.mod 的 DIR 的 use 指令
+}
+
+// HasDrift reports whether any difference was found
+// HasDrift 判断是否发现任何差异
+func (d *DiffResult) HasDrift() bool {
+	return len(d.Added) > 0 || len(d.Missing) > 0 || len(d.Stale) > 0
+}
+
+// Diff compares scanned modules against the declared use directives
+// Scanned modules without go.mod are ignored, entries keep the order of their inputs
+//
+// Diff 将扫描到的模块与声明的 use 指令进行比较
+// 忽略没有 go.mod 的扫描结果，条目保持输入的顺序
+func Diff(scanned []workspath.Module, declared []Use) *DiffResult {
+	res := &DiffResult{
+		Added:   []DiffEntry{},
+		Missing: []DiffEntry{},
+		Stale:   []DiffEntry{},
+	}
+
+	declaredDIRs := map[string]bool{}
+	for _, use := range declared {
+		dir := filepath.Clean(use.Dir)
+		declaredDIRs[dir] = true
+
+		entry := DiffEntry{Dir: dir, Use: use.Path, Module: use.ModulePath}
+		if _, err := os.Stat(dir); err != nil {
+			res.Missing = append(res.Missing, entry)
+		} else if !osomitexist.IsFile(filepath.Join(dir, "go.mod")) {
+			res.Stale = append(res.Stale, entry)
+		}
+	}
+
+	for _, module := range scanned {
+		if module.File == nil {
+			continue
+		}
+		if dir := filepath.Clean(module.Path); !declaredDIRs[dir] {
+			res.Added = append(res.Added, DiffEntry{Dir: dir, Module: module.ModulePath})
+		}
+	}
+	return res
+}
@@ -0,0 +1,49 @@
+package workspace_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-mate/go-work/internal/testfs"
+	"github.com/go-mate/go-work/workspace"
+	"github.com/go-mate/go-work/workspath"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/rese"
+)
+
+// TestDiff tests drift detection between scanned modules and go.work
+// TestDiff 测试扫描到的模块与 go.work 之间的偏差检测
+func TestDiff(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "test-workspace-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	for _, name := range []string{"api", "lib", "tools"} {
+		testfs.WriteGoMod(t, filepath.Join(tempDIR, name), "example.com/"+name, "")
+		testfs.WriteFile(t, filepath.Join(tempDIR, name, "main.go"), "package main\n")
+	}
+	must.Done(os.MkdirAll(filepath.Join(tempDIR, "docs"), 0755))
+	testfs.WriteFile(t, filepath.Join(tempDIR, "go.work"), "go 1.22.8\n\nuse (\n\t./api\n\t./docs\n\t./gone\n\t./lib\n)\n")
+
+	ws, err := workspace.Load(tempDIR)
+	require.Error(t, err)
+	modules := rese.V1(workspath.ScanModules(tempDIR, workspath.ScanDeep(), workspath.SkipNoGo()))
+
+	res := workspace.Diff(modules, ws.Uses)
+	t.Log(neatjsons.S(res))
+
+	require.True(t, res.HasDrift())
+	require.Equal(t, []workspace.DiffEntry{{Dir: filepath.Join(tempDIR, "tools"), Module: "example.com/tools"}}, res.Added)
+	require.Equal(t, []workspace.DiffEntry{{Dir: filepath.Join(tempDIR, "gone"), Use: "./gone"}}, res.Missing)
+	require.Equal(t, []workspace.DiffEntry{{Dir: filepath.Join(tempDIR, "docs"), Use: "./docs"}}, res.Stale)
+
+	// Once go.work is synced there is no drift
+	ws.Projects = []string{filepath.Join(tempDIR, "api"), filepath.Join(tempDIR, "lib"), filepath.Join(tempDIR, "tools")}
+	must.Done(ws.WriteGoWork())
+	ws = rese.P1(workspace.Load(tempDIR))
+	require.False(t, workspace.Diff(modules, ws.Uses).HasDrift())
+}
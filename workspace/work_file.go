@@ -0,0 +1,160 @@
+package workspace
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-mate/go-work/workspath"
+	"golang.org/x/mod/modfile"
+)
+
+// ErrNoGoMod reports a use directive pointing at a DIR without go.mod
+// ErrNoGoMod 表示 use 指令指向的 DIR 中没有 go.mod
+var ErrNoGoMod = errors.New("go.mod not found")
+
+// Use is one use directive of go.work
+// Use 是 go.work 中的一条 use 指令
+type Use struct {
+	Path       string // Path as written in go.work // go.work 中书写的路径
+	Dir        string // Absolute DIR the path resolves to // 路径解析得到的绝对 DIR
+	ModulePath string // Module path declared in the go.mod of Dir // Dir 中 go.mod 声明的模块路径
+}
+
+// Godebug is one godebug setting of go.work, shared with go.mod
+// Godebug 是 go.work 中的一条 godebug 设置，与 go.mod 共用
+type Godebug = workspath.Godebug
+
+// UseError records a use directive that does not point at a valid module
+// UseError 记录未指向有效模块的 use 指令
+type UseError struct {
+	Use Use   // The invalid use directive // 无效的 use 指令
+	Err error // Underlying cause // 底层原因
+}
+
+// Error returns the use path together with the cause
+// Error 返回 use 路径及其原因
+func (e *UseError) Error() string {
+	return "use " + e.Use.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying cause
+// Unwrap 返回底层原因
+func (e *UseError) Unwrap() error {
+	return e.Err
+}
+
+// UseErrors collects the invalid use directives of go.work
+// UseErrors 收集 go.work 中无效的 use 指令
+type UseErrors []*UseError
+
+// Error joins each use error on its own line
+// Error 将每个 use 错误按行拼接
+func (es UseErrors) Error() string {
+	lines := make([]string, 0, len(es))
+	for _, e := range es {
+		lines = append(lines, e.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap exposes the collected errors to errors.Is and errors.As
+// Unwrap 将收集的错误暴露给 errors.Is 和 errors.As
+func (es UseErrors) Unwrap() []error {
+	errs := make([]error, 0, len(es))
+	for _, e := range es {
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+// Load reads the go.work file in workRoot and creates the workspace it describes
+// Each use directive is resolved to an absolute DIR, valid ones become Projects
+// Invalid use directives are returned as UseErrors together with the loaded workspace
+//
+// Load 读取 workRoot 中的 go.work 文件并创建其描述的工作区
+// 每条 use 指令解析为绝对 DIR，有效的成为 Projects
+// 无效的 use 指令以 UseErrors 形式与已加载的工作区一起返回
+func Load(workRoot string) (*Workspace, error) {
+	workRoot, err := filepath.Abs(workRoot)
+	if err != nil {
+		return nil, err
+	}
+	workPath := filepath.Join(workRoot, "go.work")
+	content, err := os.ReadFile(workPath)
+	if err != nil {
+		return nil, err
+	}
+	workFile, err := modfile.ParseWork(workPath, content, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ws := &Workspace{
+		WorkRoot: workRoot,
+		WorkFile: workFile,
+	}
+	if workFile.Go != nil {
+		ws.GoVersion = workFile.Go.Version
+	}
+	if workFile.Toolchain != nil {
+		ws.Toolchain = workFile.Toolchain.Name
+	}
+	for _, godebug := range workFile.Godebug {
+		ws.Godebug = append(ws.Godebug, Godebug{Key: godebug.Key, Value: godebug.Value})
+	}
+	for _, rep := range workFile.Replace {
+		ws.Replace = append(ws.Replace, workspath.Replace{Old: rep.Old, New: rep.New})
+	}
+
+	var useErrs UseErrors
+	for _, use := range workFile.Use {
+		item := Use{
+			Path: use.Path,
+			Dir:  resolveUseDIR(workRoot, use.Path),
+		}
+		modulePath, err := readUseModule(item.Dir)
+		item.ModulePath = modulePath
+		ws.Uses = append(ws.Uses, item)
+		if err != nil {
+			useErrs = append(useErrs, &UseError{Use: item, Err: err})
+			continue
+		}
+		ws.Projects = append(ws.Projects, item.Dir)
+	}
+	if len(useErrs) > 0 {
+		return ws, useErrs
+	}
+	return ws, nil
+}
+
+// resolveUseDIR converts a use path, relative to workRoot unless absolute, into a clean absolute DIR
+// resolveUseDIR 将 use 路径（非绝对路径时相对于 workRoot）转换为规范的绝对 DIR
+func resolveUseDIR(workRoot string, usePath string) string {
+	dir := filepath.FromSlash(usePath)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(workRoot, dir)
+	}
+	return filepath.Clean(dir)
+}
+
+// readUseModule verifies that the use DIR contains go.mod and returns the declared module path
+// readUseModule 验证 use DIR 包含 go.mod 并返回其声明的模块路径
+func readUseModule(dir string) (string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", errors.New("not a DIR")
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNoGoMod
+		}
+		return "", err
+	}
+	return modfile.ModulePath(content), nil
+}
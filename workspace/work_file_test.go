@@ -0,0 +1,114 @@
+package workspace_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-mate/go-work/internal/testfs"
+	"github.com/go-mate/go-work/workspace"
+	"github.com/go-mate/go-work/workspath"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/rese"
+	"golang.org/x/mod/module"
+)
+
+// TestLoad tests loading a workspace from go.work
+// Verifies directives are exposed and use paths are resolved
+//
+// TestLoad 测试从 go.work 加载工作区
+// 验证指令被暴露且 use 路径被解析
+func TestLoad(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "test-workspace-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "api"), "example.com/api", "")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "lib"), "example.com/lib", "")
+	testfs.WriteFile(t, filepath.Join(tempDIR, "go.work"), `go 1.22.8
+
+toolchain go1.23.4
+
+godebug default=go1.21
+
+use (
+	./api
+	./lib
+)
+
+replace example.com/dep v1.0.0 => ../dep
+`)
+
+	ws, err := workspace.Load(tempDIR)
+	require.NoError(t, err)
+	t.Log(neatjsons.S(ws))
+
+	require.Equal(t, tempDIR, ws.WorkRoot)
+	require.Equal(t, []string{filepath.Join(tempDIR, "api"), filepath.Join(tempDIR, "lib")}, ws.Projects)
+	require.Equal(t, "1.22.8", ws.GoVersion)
+	require.Equal(t, "go1.23.4", ws.Toolchain)
+	require.Equal(t, []workspace.Godebug{{Key: "default", Value: "go1.21"}}, ws.Godebug)
+	require.Equal(t, []workspath.Replace{{
+		Old: module.Version{Path: "example.com/dep", Version: "v1.0.0"},
+		New: module.Version{Path: "../dep"},
+	}}, ws.Replace)
+	require.Len(t, ws.Uses, 2)
+	require.Equal(t, "./lib", ws.Uses[1].Path)
+	require.Equal(t, "example.com/lib", ws.Uses[1].ModulePath)
+	require.NotNil(t, ws.WorkFile)
+}
+
+// TestLoad_InvalidUse tests that missing and mis-pointed use entries are reported
+// TestLoad_InvalidUse 测试缺失和指向错误的 use 条目被报告
+func TestLoad_InvalidUse(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "test-workspace-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "api"), "example.com/api", "")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "lib"), "example.com/lib", "")
+	must.Done(os.MkdirAll(filepath.Join(tempDIR, "docs"), 0755))
+	testfs.WriteFile(t, filepath.Join(tempDIR, "go.work"), `go 1.22.8
+
+use (
+	./api
+	./docs
+	./gone
+	./lib/go.mod
+)
+`)
+
+	ws, err := workspace.Load(tempDIR)
+	require.Error(t, err)
+	t.Log(err)
+
+	require.NotNil(t, ws)
+	require.Equal(t, []string{filepath.Join(tempDIR, "api")}, ws.Projects)
+	require.Len(t, ws.Uses, 4)
+
+	var useErrs workspace.UseErrors
+	require.True(t, errors.As(err, &useErrs))
+	require.Len(t, useErrs, 3)
+	require.ErrorIs(t, useErrs[0], workspace.ErrNoGoMod)
+	require.ErrorIs(t, useErrs[1], fs.ErrNotExist)
+	require.Equal(t, "./lib/go.mod", useErrs[2].Use.Path)
+}
+
+// TestLoad_NoGoWork tests that a missing go.work is an error
+// TestLoad_NoGoWork 测试缺失 go.work 时返回错误
+func TestLoad_NoGoWork(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "test-workspace-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	ws, err := workspace.Load(tempDIR)
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	require.Nil(t, ws)
+}
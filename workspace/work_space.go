@@ -10,8 +10,10 @@ package workspace
 import (
 	"path/filepath"
 
+	"github.com/go-mate/go-work/workspath"
 	"github.com/yyle88/must"
 	"github.com/yyle88/osexistpath/osmustexist"
+	"golang.org/x/mod/modfile"
 )
 
 // Workspace represents a Go workspace DIR containing multiple subprojects
@@ -24,6 +26,15 @@ import (
 type Workspace struct {
 	WorkRoot string   // Root DIR of the workspace // 工作区根目录
 	Projects []string // Project paths within this workspace // 该工作区内的项目路径
+
+	// Fields below are set by Load from the go.work file
+	// 以下字段由 Load 根据 go.work 文件设置
+	GoVersion string              // Version in the go directive // go 指令中的版本
+	Toolchain string              // Name in the toolchain directive // toolchain 指令中的名称
+	Godebug   []Godebug           // Godebug settings // godebug 设置
+	Replace   []workspath.Replace // Replace directives // replace 指令
+	Uses      []Use               // Every use directive, including invalid ones // 全部 use 指令，包括无效的
+	WorkFile  *modfile.WorkFile   `json:"-"` // Parsed go.work // 解析后的 go.work
 }
 
 // NewWorkSpace creates a new workspace without a root DIR
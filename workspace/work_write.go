@@ -0,0 +1,150 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-mate/go-work/internal/gover"
+	"github.com/go-mate/go-work/workspath"
+	"golang.org/x/mod/modfile"
+)
+
+// FormatGoWork renders the go.work describing the workspace Projects
+// Starts from the existing go.work so comments, godebug and replace directives are kept
+// Use paths are relative to WorkRoot, the go version is the highest among the project go.mod files
+//
+// FormatGoWork 生成描述工作区 Projects 的 go.work 内容
+// 以已有的 go.work 为基础，保留注释、godebug 和 replace 指令
+// use 路径相对于 WorkRoot，go 版本取各项目 go.mod 中的最高版本
+func (ws *Workspace) FormatGoWork() ([]byte, error) {
+	workFile, err := ws.cloneWorkFile()
+	if err != nil {
+		return nil, err
+	}
+
+	goVersions := make([]string, 0, len(ws.Projects))
+	for _, projectPath := range ws.Projects {
+		goVersion, err := readGoVersion(projectPath)
+		if err != nil {
+			return nil, err
+		}
+		goVersions = append(goVersions, goVersion)
+	}
+	if goVersion := gover.Max(goVersions...); goVersion != "" {
+		if err := workFile.AddGoStmt(goVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	// Keep use directives still pointing at projects, as written, and drop the others
+	// 保留仍指向项目的 use 指令（保持原写法），删除其余的
+	need := map[string]bool{}
+	for _, projectPath := range ws.Projects {
+		need[filepath.Clean(projectPath)] = true
+	}
+	have := map[string]bool{}
+	for _, use := range workFile.Use {
+		dir := resolveUseDIR(ws.WorkRoot, use.Path)
+		if need[dir] && !have[dir] {
+			have[dir] = true
+			continue
+		}
+		if err := workFile.DropUse(use.Path); err != nil {
+			return nil, err
+		}
+	}
+	for _, projectPath := range ws.Projects {
+		if dir := filepath.Clean(projectPath); !have[dir] {
+			have[dir] = true
+			workFile.AddNewUse(relUsePath(ws.WorkRoot, dir), "")
+		}
+	}
+	workFile.SortBlocks()
+	workFile.Cleanup()
+	return modfile.Format(workFile.Syntax), nil
+}
+
+// WriteGoWork writes the FormatGoWork result into the go.work of WorkRoot
+// WriteGoWork 将 FormatGoWork 的结果写入 WorkRoot 的 go.work
+func (ws *Workspace) WriteGoWork() error {
+	content, err := ws.FormatGoWork()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(ws.WorkRoot, "go.work"), content, 0644)
+}
+
+// cloneWorkFile returns a copy of the go.work to edit, blank when there is none
+// Re-parses the loaded file so FormatGoWork never changes WorkFile
+//
+// cloneWorkFile 返回待编辑的 go.work 副本，不存在时返回空文件
+// 重新解析已加载的文件，使 FormatGoWork 不会修改 WorkFile
+func (ws *Workspace) cloneWorkFile() (*modfile.WorkFile, error) {
+	workPath := filepath.Join(ws.WorkRoot, "go.work")
+	var content []byte
+	if ws.WorkFile != nil {
+		content = modfile.Format(ws.WorkFile.Syntax)
+	} else {
+		data, err := os.ReadFile(workPath)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		content = data
+	}
+	return modfile.ParseWork(workPath, content, nil)
+}
+
+// readGoVersion returns the go directive version of the go.mod in DIR, blank when missing
+// readGoVersion 返回 DIR 中 go.mod 的 go 指令版本，缺失时返回空
+func readGoVersion(dir string) (string, error) {
+	modPath := filepath.Join(dir, "go.mod")
+	content, err := os.ReadFile(modPath)
+	if err != nil {
+		return "", err
+	}
+	modFile, err := modfile.ParseLax(modPath, content, nil)
+	if err != nil {
+		return "", err
+	}
+	if modFile.Go == nil {
+		return "", nil
+	}
+	return modFile.Go.Version, nil
+}
+
+// relUsePath converts DIR into a use path relative to workRoot, like "./api" or "../lib"
+// relUsePath 将 DIR 转换为相对 workRoot 的 use 路径，如 "./api" 或 "../lib"
+func relUsePath(workRoot string, dir string) string {
+	rel, err := filepath.Rel(workRoot, dir)
+	if err != nil {
+		return filepath.ToSlash(dir)
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, "../") {
+		return rel
+	}
+	return "./" + rel
+}
+
+// EditGoWork applies edit to the go.work of workRoot and returns the change without writing it
+// The file is parsed again from disk, so comments and layout are kept
+//
+// EditGoWork 对 workRoot 的 go.work 应用 edit 并返回变更，但不写入
+// 文件从磁盘重新解析，因此保留注释和布局
+func EditGoWork(workRoot string, edit func(workFile *modfile.WorkFile) error) (*workspath.Change, error) {
+	workPath := filepath.Join(workRoot, "go.work")
+	content, err := os.ReadFile(workPath)
+	if err != nil {
+		return nil, err
+	}
+	workFile, err := modfile.ParseWork(workPath, content, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := edit(workFile); err != nil {
+		return nil, err
+	}
+	workFile.Cleanup()
+	return &workspath.Change{Path: workPath, Old: content, New: modfile.Format(workFile.Syntax)}, nil
+}
@@ -0,0 +1,111 @@
+package workspace_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-mate/go-work/internal/testfs"
+	"github.com/go-mate/go-work/workspace"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+	"golang.org/x/mod/modfile"
+)
+
+// TestWorkspace_FormatGoWork tests creating go.work without an existing file
+// TestWorkspace_FormatGoWork 测试在没有已有文件时创建 go.work
+func TestWorkspace_FormatGoWork(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "test-workspace-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	testfs.WriteFile(t, filepath.Join(tempDIR, "go.mod"), "module example.com/root\n\ngo 1.21\n")
+	must.Done(os.MkdirAll(filepath.Join(tempDIR, "lib"), 0755))
+	testfs.WriteFile(t, filepath.Join(tempDIR, "lib", "go.mod"), "module example.com/lib\n\ngo 1.22.8\n")
+
+	ws := &workspace.Workspace{
+		WorkRoot: tempDIR,
+		Projects: []string{tempDIR, filepath.Join(tempDIR, "lib")},
+	}
+	content := rese.V1(ws.FormatGoWork())
+	require.Equal(t, "go 1.22.8\n\nuse (\n\t.\n\t./lib\n)\n", string(content))
+
+	must.Done(ws.WriteGoWork())
+	require.Equal(t, content, rese.V1(os.ReadFile(filepath.Join(tempDIR, "go.work"))))
+}
+
+// TestWorkspace_WriteGoWork tests syncing an existing go.work
+// Verifies comments, godebug and replace directives are kept and stale uses are dropped
+//
+// TestWorkspace_WriteGoWork 测试同步已有的 go.work
+// 验证注释、godebug 和 replace 指令被保留，过期的 use 被删除
+func TestWorkspace_WriteGoWork(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "test-workspace-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "api"), "example.com/api", "")
+	testfs.WriteGoMod(t, filepath.Join(tempDIR, "lib"), "example.com/lib", "")
+	testfs.WriteFile(t, filepath.Join(tempDIR, "go.work"), `// Shared workspace of the services
+go 1.21
+
+godebug default=go1.21
+
+use (
+	api // the public API
+	./old
+)
+
+// Local fork until upstream merges the fix
+replace example.com/dep => ../dep
+`)
+
+	ws, err := workspace.Load(tempDIR)
+	require.Error(t, err) // ./old is missing
+	ws.Projects = []string{filepath.Join(tempDIR, "api"), filepath.Join(tempDIR, "lib")}
+
+	must.Done(ws.WriteGoWork())
+	require.Equal(t, `// Shared workspace of the services
+go 1.22
+
+godebug default=go1.21
+
+use (
+	./lib
+	api // the public API
+)
+
+// Local fork until upstream merges the fix
+replace example.com/dep => ../dep
+`, string(rese.V1(os.ReadFile(filepath.Join(tempDIR, "go.work")))))
+}
+
+// TestEditGoWork tests go and toolchain lines are rewritten while comments stay
+// TestEditGoWork 测试改写 go 和 toolchain 行时保留注释
+func TestEditGoWork(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "test-workspace-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	testfs.WriteFile(t, filepath.Join(tempDIR, "go.work"), "// shared workspace\ngo 1.22.8\n\nuse ./api // service\n")
+
+	change, err := workspace.EditGoWork(tempDIR, func(workFile *modfile.WorkFile) error {
+		if err := workFile.AddGoStmt("1.23.4"); err != nil {
+			return err
+		}
+		return workFile.AddToolchainStmt("go1.23.4")
+	})
+	require.NoError(t, err)
+	require.True(t, change.Changed())
+	require.Equal(t, "// shared workspace\ngo 1.23.4\n\ntoolchain go1.23.4\n\nuse ./api // service\n", string(change.New))
+
+	require.NoError(t, change.Write())
+	require.Equal(t, string(change.New), string(rese.V1(os.ReadFile(filepath.Join(tempDIR, "go.work")))))
+
+	_, err = workspace.EditGoWork(filepath.Join(tempDIR, "missing"), func(workFile *modfile.WorkFile) error { return nil })
+	require.Error(t, err)
+}
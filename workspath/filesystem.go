@@ -0,0 +1,93 @@
+package workspath
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/yyle88/osexistpath/osomitexist"
+)
+
+// fileSystem abstracts file access and path handling
+// Lets scans run on the OS filesystem or on an fs.FS with the same code
+//
+// fileSystem 抽象文件访问和路径处理
+// 使扫描可以用同一份代码运行在操作系统文件系统或 fs.FS 上
+type fileSystem interface {
+	ReadDir(name string) ([]fs.DirEntry, error)
+	ReadFile(name string) ([]byte, error)
+	IsFile(name string) bool
+	Exists(name string) bool
+	Join(elem ...string) string
+	Dir(name string) string
+	Base(name string) string
+	// Rel returns target relative to base in slash form, false when target is outside base
+	// Rel 以斜杠形式返回 target 相对 base 的路径，target 不在 base 内时返回 false
+	Rel(base string, target string) (string, bool)
+}
+
+// osFileSystem accesses the OS filesystem using native paths
+// osFileSystem 使用本地路径访问操作系统文件系统
+type osFileSystem struct{}
+
+func (osFileSystem) ReadDir(name string) ([]fs.DirEntry, error) { return os.ReadDir(name) }
+func (osFileSystem) ReadFile(name string) ([]byte, error)       { return os.ReadFile(name) }
+func (osFileSystem) IsFile(name string) bool                    { return osomitexist.IsFile(name) }
+func (osFileSystem) Exists(name string) bool                    { return osomitexist.IsPath(name) }
+func (osFileSystem) Join(elem ...string) string                 { return filepath.Join(elem...) }
+func (osFileSystem) Dir(name string) string                     { return filepath.Dir(name) }
+func (osFileSystem) Base(name string) string                    { return filepath.Base(name) }
+
+func (osFileSystem) Rel(base string, target string) (string, bool) {
+	rel, err := filepath.Rel(base, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// fsFileSystem accesses an fs.FS using slash separated paths, "." being the FS root
+// fsFileSystem 使用斜杠分隔的路径访问 fs.FS，"." 表示 FS 根
+type fsFileSystem struct {
+	fsys fs.FS
+}
+
+func (f fsFileSystem) ReadDir(name string) ([]fs.DirEntry, error) { return fs.ReadDir(f.fsys, name) }
+func (f fsFileSystem) ReadFile(name string) ([]byte, error)       { return fs.ReadFile(f.fsys, name) }
+func (f fsFileSystem) Join(elem ...string) string                 { return path.Join(elem...) }
+func (f fsFileSystem) Dir(name string) string                     { return path.Dir(name) }
+func (f fsFileSystem) Base(name string) string                    { return path.Base(name) }
+
+func (f fsFileSystem) IsFile(name string) bool {
+	info, err := fs.Stat(f.fsys, name)
+	return err == nil && !info.IsDir()
+}
+
+func (f fsFileSystem) Exists(name string) bool {
+	_, err := fs.Stat(f.fsys, name)
+	return err == nil
+}
+
+func (f fsFileSystem) Rel(base string, target string) (string, bool) {
+	switch {
+	case base == target:
+		return ".", true
+	case base == ".":
+		return target, true
+	case strings.HasPrefix(target, base+"/"):
+		return target[len(base)+1:], true
+	default:
+		return "", false
+	}
+}
+
+// newFileSystem returns fsys wrapped as fileSystem, the OS filesystem when fsys is nil
+// newFileSystem 将 fsys 包装为 fileSystem，fsys 为 nil 时返回操作系统文件系统
+func newFileSystem(fsys fs.FS) fileSystem {
+	if fsys == nil {
+		return osFileSystem{}
+	}
+	return fsFileSystem{fsys: fsys}
+}
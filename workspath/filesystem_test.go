@@ -0,0 +1,70 @@
+package workspath
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/neatjson/neatjsons"
+)
+
+// newTestFS creates an in-memory workspace with nested, empty and ignored modules
+// newTestFS 创建带嵌套、空和被忽略模块的内存工作区
+func newTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		"go.mod":                      {Data: []byte("module test\n")},
+		"main.go":                     {Data: []byte("package main\n")},
+		".gitignore":                  {Data: []byte("out/\n")},
+		"services/api/go.mod":         {Data: []byte("module test/services/api\n")},
+		"services/api/pkg/api.go":     {Data: []byte("package pkg\n")},
+		"services/empty/go.mod":       {Data: []byte("module test/services/empty\n")},
+		"services/empty/README.md":    {Data: []byte("empty\n")},
+		"out/cache/go.mod":            {Data: []byte("module cache\n")},
+		"out/cache/cache.go":          {Data: []byte("package cache\n")},
+		"vendor/example.com/x/go.mod": {Data: []byte("module example.com/x\n")},
+	}
+}
+
+// TestScanModules_WithFS tests scanning an in-memory filesystem
+// TestScanModules_WithFS 测试扫描内存文件系统
+func TestScanModules_WithFS(t *testing.T) {
+	fsys := newTestFS()
+
+	paths := GetModulePaths(".", WithFS(fsys), ScanDeep())
+	t.Log("paths:", neatjsons.S(paths))
+	require.Equal(t, []string{".", "out/cache", "services/api", "services/empty"}, paths)
+
+	paths = GetModulePaths(".", WithFS(fsys), ScanDeep(), SkipNoGo(), RespectGitignore())
+	require.Equal(t, []string{".", "services/api"}, paths)
+
+	paths = GetModulePaths("services/api/pkg", WithFS(fsys), WithCurrentProject())
+	require.Equal(t, []string{"services/api"}, paths)
+
+	modules, err := ScanModules("missing", WithFS(fsys), ScanDeep())
+	require.Error(t, err)
+	require.Empty(t, modules)
+}
+
+// TestGetProjectPathFS tests project detection on an in-memory filesystem
+// TestGetProjectPathFS 测试在内存文件系统上发现项目
+func TestGetProjectPathFS(t *testing.T) {
+	fsys := newTestFS()
+
+	info, ok := GetProjectPathFS(fsys, "services/api/pkg")
+	require.True(t, ok)
+	require.Equal(t, "services/api", info.Root)
+	require.Equal(t, "pkg", info.SubPath)
+
+	info, ok = GetProjectPathFS(fsys, "services")
+	require.True(t, ok)
+	require.Equal(t, ".", info.Root)
+	require.Equal(t, "services", info.SubPath)
+
+	root, ok := GetProjectRootFS(fsys, "services/empty")
+	require.True(t, ok)
+	require.Equal(t, "services/empty", root)
+
+	root, ok = GetProjectRootFS(fstest.MapFS{"a/b.txt": {}}, "a")
+	require.False(t, ok)
+	require.Empty(t, root)
+}
@@ -0,0 +1,243 @@
+package workspath
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+)
+
+// defaultSkipNames are DIR names skipped by default
+// Together with the . prefix rule in pathFilter.skip and the _ one of SkipUnderscore, this matches what the go tool ignores
+//
+// defaultSkipNames 是默认跳过的 DIR 名称
+// 与 pathFilter.skip 中的 . 前缀规则以及 SkipUnderscore 的 _ 前缀规则一起，与 go 工具忽略的内容保持一致
+var defaultSkipNames = map[string]bool{
+	"vendor":   true,
+	"testdata": true,
+}
+
+// ignorePattern is one parsed gitignore-style pattern
+// ignorePattern 是一条已解析的 gitignore 风格模式
+type ignorePattern struct {
+	segments []string // Slash separated glob segments // 斜杠分隔的 glob 片段
+	negate   bool     // Pattern starts with "!" // 模式以 "!" 开头
+	dirOnly  bool     // Pattern ends with "/" // 模式以 "/" 结尾
+}
+
+// parseIgnorePattern parses one gitignore line, returns nil on blank lines and comments
+// parseIgnorePattern 解析一行 gitignore，空行和注释返回 nil
+func parseIgnorePattern(line string) *ignorePattern {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+	pattern := &ignorePattern{}
+	if strings.HasPrefix(line, "!") {
+		pattern.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		pattern.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return nil
+	}
+	// Patterns without an inner slash match at any depth
+	// 不含内部斜杠的模式在任意深度匹配
+	if !strings.Contains(line, "/") {
+		line = "**/" + line
+	}
+	pattern.segments = strings.Split(strings.TrimPrefix(line, "/"), "/")
+	return pattern
+}
+
+// match checks the slash separated path, relative to the pattern base
+// match 检查相对于模式基准目录的斜杠分隔路径
+func (p *ignorePattern) match(rel string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+	return matchSegments(p.segments, strings.Split(rel, "/"))
+}
+
+// matchSegments matches glob segments against path segments, "**" spans any count of segments
+// matchSegments 将 glob 片段与路径片段匹配，"**" 可匹配任意数量的片段
+func matchSegments(globs []string, names []string) bool {
+	for len(globs) > 0 {
+		if globs[0] == "**" {
+			for skip := 0; skip <= len(names); skip++ {
+				if matchSegments(globs[1:], names[skip:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(names) == 0 {
+			return false
+		}
+		if ok, err := path.Match(globs[0], names[0]); err != nil || !ok {
+			return false
+		}
+		globs, names = globs[1:], names[1:]
+	}
+	return len(names) == 0
+}
+
+// parseIgnoreFile reads patterns from a gitignore-style file, a missing file yields none
+// parseIgnoreFile 从 gitignore 风格文件读取模式，文件不存在时返回空
+func parseIgnoreFile(files fileSystem, path string) ([]*ignorePattern, error) {
+	content, err := files.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var patterns []*ignorePattern
+	lines := bufio.NewScanner(bytes.NewReader(content))
+	for lines.Scan() {
+		if pattern := parseIgnorePattern(lines.Text()); pattern != nil {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns, lines.Err()
+}
+
+// pathFilter decides which paths a scan skips
+// Combines hidden entries, default skipped DIRs, exclude globs and gitignore files
+//
+// pathFilter 决定扫描跳过哪些路径
+// 综合隐藏条目、默认跳过的 DIR、排除 glob 和 gitignore 文件
+type pathFilter struct {
+	files          fileSystem                  // Filesystem being scanned // 被扫描的文件系统
+	root           string                      // Scan root that exclude globs are relative to // 排除 glob 相对的扫描根
+	excludes       []*ignorePattern            // Patterns from WithExclude // 来自 WithExclude 的模式
+	skipUnderscore bool                        // Skip names starting with _, from SkipUnderscore // 跳过以 _ 开头的名称，来自 SkipUnderscore
+	topDIR         string                      // Highest DIR whose ignore files are read // 读取忽略文件的最高 DIR
+	rules          map[string][]*ignorePattern // Loaded gitignore patterns per DIR // 每个 DIR 已加载的 gitignore 模式
+	mutex          sync.Mutex                  // Guards rules across concurrent walkers // 在并发遍历间保护 rules
+}
+
+// newPathFilter creates the filter used when scanning root
+// newPathFilter 创建扫描 root 时使用的过滤器
+func newPathFilter(files fileSystem, root string, cfg *scanConfig) *pathFilter {
+	filter := &pathFilter{files: files, root: root, skipUnderscore: cfg.skipUnderscore}
+	for _, glob := range cfg.excludes {
+		if pattern := parseIgnorePattern(glob); pattern != nil {
+			filter.excludes = append(filter.excludes, pattern)
+		}
+	}
+	if cfg.respectGitignore {
+		filter.rules = map[string][]*ignorePattern{}
+		filter.topDIR = findGitRoot(files, root)
+	}
+	return filter
+}
+
+// findGitRoot returns the nearest DIR containing .git, or root when none is found
+// findGitRoot 返回最近的包含 .git 的 DIR，找不到时返回 root
+func findGitRoot(files fileSystem, root string) string {
+	for path := root; ; {
+		if files.Exists(files.Join(path, ".git")) {
+			return path
+		}
+		parent := files.Dir(path)
+		if parent == path {
+			return root
+		}
+		path = parent
+	}
+}
+
+// skip reports whether the walk should skip path, callers never pass the walk root
+// Names starting with . are skipped, and with _ too under SkipUnderscore, for files and DIRs alike
+// Returns an error when an ignore file exists but cannot be read
+//
+// skip 判断遍历是否应跳过 path，调用方不会传入遍历根
+// 跳过以 . 开头的名称，设置 SkipUnderscore 时也跳过以 _ 开头的名称，文件和 DIR 均适用
+// 忽略文件存在但无法读取时返回错误
+func (f *pathFilter) skip(path string, isDir bool) (bool, error) {
+	name := f.files.Base(path)
+	if strings.HasPrefix(name, ".") || f.skipUnderscore && strings.HasPrefix(name, "_") {
+		return true, nil
+	}
+	if isDir && defaultSkipNames[name] {
+		return true, nil
+	}
+	if rel, ok := f.files.Rel(f.root, path); ok {
+		for _, pattern := range f.excludes {
+			if pattern.match(rel, isDir) {
+				return true, nil
+			}
+		}
+	}
+	if f.rules != nil {
+		return f.ignored(path, isDir)
+	}
+	return false, nil
+}
+
+// ignored applies the gitignore files from topDIR down to the parent of path
+// Later patterns override earlier ones, so deeper files take precedence
+//
+// ignored 从 topDIR 向下直到 path 的父目录依次应用 gitignore 文件
+// 后出现的模式覆盖先出现的，因此更深层的文件优先
+func (f *pathFilter) ignored(path string, isDir bool) (bool, error) {
+	if _, ok := f.files.Rel(f.topDIR, path); !ok {
+		return false, nil
+	}
+	var chain []string
+	for dir := f.files.Dir(path); ; dir = f.files.Dir(dir) {
+		chain = append(chain, dir)
+		if dir == f.topDIR || dir == f.files.Dir(dir) {
+			break
+		}
+	}
+	ignored := false
+	for idx := len(chain) - 1; idx >= 0; idx-- {
+		dir := chain[idx]
+		patterns, err := f.loadRules(dir)
+		if err != nil {
+			return false, err
+		}
+		rel, _ := f.files.Rel(dir, path)
+		for _, pattern := range patterns {
+			if pattern.match(rel, isDir) {
+				ignored = !pattern.negate
+			}
+		}
+	}
+	return ignored, nil
+}
+
+// loadRules reads and caches the ignore patterns defined in DIR
+// loadRules 读取并缓存 DIR 中定义的忽略模式
+func (f *pathFilter) loadRules(dir string) ([]*ignorePattern, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if patterns, ok := f.rules[dir]; ok {
+		return patterns, nil
+	}
+	var patterns []*ignorePattern
+	if dir == f.topDIR {
+		excludes, err := parseIgnoreFile(f.files, f.files.Join(dir, ".git", "info", "exclude"))
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, excludes...)
+	}
+	ignores, err := parseIgnoreFile(f.files, f.files.Join(dir, ".gitignore"))
+	if err != nil {
+		return nil, err
+	}
+	patterns = append(patterns, ignores...)
+	f.rules[dir] = patterns
+	return patterns, nil
+}
@@ -0,0 +1,125 @@
+package workspath
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-mate/go-work/internal/testfs"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/neatjson/neatjsons"
+)
+
+// TestIgnorePattern tests gitignore pattern matching
+// TestIgnorePattern 测试 gitignore 模式匹配
+func TestIgnorePattern(t *testing.T) {
+	type testCase struct {
+		pattern string
+		rel     string
+		isDir   bool
+		match   bool
+	}
+	for _, tc := range []testCase{
+		{"node_modules", "node_modules", true, true},
+		{"node_modules", "web/node_modules", true, true},
+		{"build/", "build", true, true},
+		{"build/", "build", false, false},
+		{"/gen", "gen", true, true},
+		{"/gen", "sub/gen", true, false},
+		{"tools/**/gen", "tools/gen", true, true},
+		{"tools/**/gen", "tools/a/b/gen", true, true},
+		{"tools/**/gen", "other/gen", true, false},
+		{"*.tmp", "a/b.tmp", false, true},
+		{"!keep", "keep", true, true},
+	} {
+		pattern := parseIgnorePattern(tc.pattern)
+		require.NotNil(t, pattern, tc.pattern)
+		require.Equal(t, tc.match, pattern.match(tc.rel, tc.isDir), "%s ~ %s", tc.pattern, tc.rel)
+	}
+
+	require.Nil(t, parseIgnorePattern(""))
+	require.Nil(t, parseIgnorePattern("# comment"))
+	require.True(t, parseIgnorePattern("!keep").negate)
+}
+
+// TestScanModules_DefaultSkip tests that vendor, testdata and DIRs starting with . are skipped
+// TestScanModules_DefaultSkip 测试默认跳过 vendor、testdata 以及以 . 开头的 DIR
+func TestScanModules_DefaultSkip(t *testing.T) {
+	tempDIR := setupTestProject(t)
+	defer cleanupDIR(t, tempDIR)
+
+	testfs.WriteModule(t, filepath.Join(tempDIR, "vendor", "example.com", "dep"), "example.com/dep")
+	testfs.WriteModule(t, filepath.Join(tempDIR, "testdata", "fixture"), "fixture")
+	testfs.WriteModule(t, filepath.Join(tempDIR, ".cache", "mod"), "example.com/mod")
+
+	paths := GetModulePaths(tempDIR, ScanDeep())
+	require.Equal(t, []string{tempDIR, filepath.Join(tempDIR, "submodule")}, paths)
+}
+
+// TestScanModules_SkipUnderscore tests DIRs starting with _ are still found by default, like before, and skipped with SkipUnderscore
+// TestScanModules_SkipUnderscore 测试默认仍能找到以 _ 开头的 DIR（与之前一致），设置 SkipUnderscore 时跳过
+func TestScanModules_SkipUnderscore(t *testing.T) {
+	tempDIR := setupTestProject(t)
+	defer cleanupDIR(t, tempDIR)
+
+	testfs.WriteModule(t, filepath.Join(tempDIR, "_examples", "demo"), "example.com/demo")
+
+	paths := GetModulePaths(tempDIR, ScanDeep())
+	require.Equal(t, []string{tempDIR, filepath.Join(tempDIR, "_examples", "demo"), filepath.Join(tempDIR, "submodule")}, paths)
+
+	paths = GetModulePaths(tempDIR, ScanDeep(), SkipUnderscore())
+	require.Equal(t, []string{tempDIR, filepath.Join(tempDIR, "submodule")}, paths)
+}
+
+// TestScanModules_WithExclude tests exclude globs
+// TestScanModules_WithExclude 测试排除 glob
+func TestScanModules_WithExclude(t *testing.T) {
+	tempDIR := setupTestProject(t)
+	defer cleanupDIR(t, tempDIR)
+
+	testfs.WriteModule(t, filepath.Join(tempDIR, "web", "node_modules", "pkg"), "pkg")
+	testfs.WriteModule(t, filepath.Join(tempDIR, "tools", "gen"), "tools/gen")
+
+	paths := GetModulePaths(tempDIR, ScanDeep())
+	require.Len(t, paths, 4)
+
+	paths = GetModulePaths(tempDIR, ScanDeep(), WithExclude("node_modules", "tools/"))
+	t.Log("paths:", neatjsons.S(paths))
+	require.Equal(t, []string{tempDIR, filepath.Join(tempDIR, "submodule")}, paths)
+}
+
+// TestScanModules_RespectGitignore tests hierarchical gitignore handling
+// TestScanModules_RespectGitignore 测试分层 gitignore 处理
+func TestScanModules_RespectGitignore(t *testing.T) {
+	tempDIR := setupTestProject(t)
+	defer cleanupDIR(t, tempDIR)
+
+	must.Done(os.MkdirAll(filepath.Join(tempDIR, ".git", "info"), 0755))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, ".git", "info", "exclude"), []byte("local/\n"), 0644))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, ".gitignore"), []byte("# build output\nout/\nthird_party/\n"), 0644))
+	testfs.WriteModule(t, filepath.Join(tempDIR, "out", "cache"), "cache")
+	testfs.WriteModule(t, filepath.Join(tempDIR, "local", "scratch"), "scratch")
+	testfs.WriteModule(t, filepath.Join(tempDIR, "third_party", "lib"), "lib")
+
+	// Nested .gitignore applies to its own subtree
+	servicesDIR := filepath.Join(tempDIR, "services")
+	testfs.WriteModule(t, filepath.Join(servicesDIR, "api"), "api")
+	testfs.WriteModule(t, filepath.Join(servicesDIR, "legacy"), "legacy")
+	must.Done(os.WriteFile(filepath.Join(servicesDIR, ".gitignore"), []byte("legacy/\n"), 0644))
+
+	paths := GetModulePaths(tempDIR, ScanDeep())
+	require.Len(t, paths, 7)
+
+	paths = GetModulePaths(tempDIR, ScanDeep(), RespectGitignore())
+	t.Log("paths:", neatjsons.S(paths))
+	require.Equal(t, []string{
+		tempDIR,
+		filepath.Join(servicesDIR, "api"),
+		filepath.Join(tempDIR, "submodule"),
+	}, paths)
+
+	// Scanning a sub DIR still honors the ignore files above it
+	paths = GetModulePaths(servicesDIR, ScanDeep(), RespectGitignore())
+	require.Equal(t, []string{filepath.Join(servicesDIR, "api")}, paths)
+}
@@ -0,0 +1,142 @@
+package workspath
+
+import (
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// Module describes a Go module found by ScanModules
+// The go.mod is parsed once during the scan, parse errors are kept in Err
+//
+// Module 描述 ScanModules 找到的 Go 模块
+// go.mod 在扫描时解析一次，解析错误保存在 Err 中
+type Module struct {
+	Path       string           `json:"path"`                // DIR containing go.mod // 包含 go.mod 的 DIR
+	RelPath    string           `json:"relPath"`             // Slash separated path from the scan root // 相对扫描根的斜杠分隔路径
+	ModulePath string           `json:"module"`              // Module path declared in go.mod // go.mod 中声明的模块路径
+	GoVersion  string           `json:"go,omitempty"`        // Version in the go directive // go 指令中的版本
+	Toolchain  string           `json:"toolchain,omitempty"` // Name in the toolchain directive // toolchain 指令中的名称
+	Godebug    []Godebug        `json:"godebug,omitempty"`   // Godebug directives // godebug 指令
+	Require    []Require        `json:"require,omitempty"`   // Require directives // require 指令
+	Replace    []Replace        `json:"replace,omitempty"`   // Replace directives // replace 指令
+	Exclude    []module.Version `json:"exclude,omitempty"`   // Exclude directives // exclude 指令
+	Retract    []Retract        `json:"retract,omitempty"`   // Retract directives // retract 指令
+	HasGoFiles bool             `json:"hasGoFiles"`          // DIR contains .go files outside nested modules // DIR 在嵌套模块之外包含 .go 文件
+	File       *modfile.File    `json:"-"`                   // Parsed go.mod, nil when missing or invalid // 解析后的 go.mod，缺失或无效时为 nil
+	Err        error            `json:"-"`                   // Error reading or parsing go.mod // 读取或解析 go.mod 的错误
+}
+
+// Godebug is one godebug setting
+// Godebug 是一条 godebug 设置
+type Godebug struct {
+	Key   string `json:"key"`   // Setting name // 设置名称
+	Value string `json:"value"` // Setting value // 设置值
+}
+
+// Require is one require directive
+// Require 是一条 require 指令
+type Require struct {
+	Path     string `json:"path"`               // Required module path // 依赖的模块路径
+	Version  string `json:"version"`            // Required version // 依赖的版本
+	Indirect bool   `json:"indirect,omitempty"` // Marked "// indirect" // 标记为 "// indirect"
+}
+
+// Replace is one replace directive
+// Replace 是一条 replace 指令
+type Replace struct {
+	Old module.Version `json:"old"` // Replaced module, version may be blank // 被替换的模块，版本可为空
+	New module.Version `json:"new"` // Replacement, a module or a filesystem path // 替换目标，模块或文件系统路径
+}
+
+// Retract is one retracted version interval
+// Retract 是一段撤回的版本区间
+type Retract struct {
+	Low       string `json:"low"`                 // Lowest retracted version // 撤回的最低版本
+	High      string `json:"high"`                // Highest retracted version // 撤回的最高版本
+	Rationale string `json:"rationale,omitempty"` // Comment explaining the retraction // 解释撤回原因的注释
+}
+
+// newModule builds the Module of DIR, parsing its go.mod when present
+// newModule 构建 DIR 的 Module，存在 go.mod 时解析它
+func newModule(files fileSystem, root string, path string, hasGoFiles bool) Module {
+	res := Module{
+		Path:       path,
+		RelPath:    relPath(files, root, path),
+		HasGoFiles: hasGoFiles,
+	}
+	modPath := files.Join(path, "go.mod")
+	if !files.IsFile(modPath) {
+		return res
+	}
+	content, err := files.ReadFile(modPath)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	modFile, err := modfile.Parse(modPath, content, nil)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	res.setModFile(modFile)
+	return res
+}
+
+// setModFile copies the directives of modFile into the module
+// setModFile 将 modFile 中的指令复制到模块
+func (m *Module) setModFile(modFile *modfile.File) {
+	m.File = modFile
+	if modFile.Module != nil {
+		m.ModulePath = modFile.Module.Mod.Path
+	}
+	if modFile.Go != nil {
+		m.GoVersion = modFile.Go.Version
+	}
+	if modFile.Toolchain != nil {
+		m.Toolchain = modFile.Toolchain.Name
+	}
+	for _, godebug := range modFile.Godebug {
+		m.Godebug = append(m.Godebug, Godebug{Key: godebug.Key, Value: godebug.Value})
+	}
+	for _, req := range modFile.Require {
+		m.Require = append(m.Require, Require{
+			Path:     req.Mod.Path,
+			Version:  req.Mod.Version,
+			Indirect: req.Indirect,
+		})
+	}
+	for _, rep := range modFile.Replace {
+		m.Replace = append(m.Replace, Replace{Old: rep.Old, New: rep.New})
+	}
+	for _, exc := range modFile.Exclude {
+		m.Exclude = append(m.Exclude, exc.Mod)
+	}
+	for _, ret := range modFile.Retract {
+		m.Retract = append(m.Retract, Retract{
+			Low:       ret.Low,
+			High:      ret.High,
+			Rationale: ret.Rationale,
+		})
+	}
+}
+
+// relPath returns path relative to root in slash form, using ".." when path is above root
+// relPath 以斜杠形式返回 path 相对 root 的路径，path 在 root 之上时使用 ".."
+func relPath(files fileSystem, root string, path string) string {
+	ups := ""
+	for base := root; ; base = files.Dir(base) {
+		if rel, ok := files.Rel(base, path); ok {
+			if ups == "" {
+				return rel
+			}
+			if rel == "." {
+				return ups[:len(ups)-1]
+			}
+			return ups + rel
+		}
+		if files.Dir(base) == base {
+			return path
+		}
+		ups += "../"
+	}
+}
@@ -0,0 +1,58 @@
+package workspath
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Change is the content of a file before and after an edit
+// Change 是文件在编辑前后的内容
+type Change struct {
+	Path string // File being edited // 被编辑的文件
+	Old  []byte // Content before the edit // 编辑前的内容
+	New  []byte // Content after the edit // 编辑后的内容
+}
+
+// Changed reports whether the edit changes the content
+// Changed 判断编辑是否改变了内容
+func (c *Change) Changed() bool {
+	return !bytes.Equal(c.Old, c.New)
+}
+
+// Write saves the new content when it differs from the old
+// Write 在新内容与旧内容不同时保存新内容
+func (c *Change) Write() error {
+	if !c.Changed() {
+		return nil
+	}
+	return os.WriteFile(c.Path, c.New, 0644)
+}
+
+// EditGoMod applies edit to the go.mod of dir and returns the change without writing it
+// The file is parsed again from disk, so comments and layout are kept and scanned modules stay untouched
+//
+// EditGoMod 对 dir 的 go.mod 应用 edit 并返回变更，但不写入
+// 文件从磁盘重新解析，因此保留注释和布局，且不影响已扫描的模块
+func EditGoMod(dir string, edit func(modFile *modfile.File) error) (*Change, error) {
+	path := filepath.Join(dir, "go.mod")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	modFile, err := modfile.Parse(path, content, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := edit(modFile); err != nil {
+		return nil, err
+	}
+	modFile.Cleanup()
+	newContent, err := modFile.Format()
+	if err != nil {
+		return nil, err
+	}
+	return &Change{Path: path, Old: content, New: newContent}, nil
+}
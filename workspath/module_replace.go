@@ -0,0 +1,98 @@
+package workspath
+
+import (
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// LocalReplace is a replace directive pointing at a DIR inside the workspace
+// LocalReplace 是指向工作区内 DIR 的 replace 指令
+type LocalReplace struct {
+	Module  string `json:"module"`  // Module path of the go.mod holding the replace // 包含该 replace 的 go.mod 的模块路径
+	Dir     string `json:"dir"`     // DIR of the go.mod holding the replace // 包含该 replace 的 go.mod 的 DIR
+	Path    string `json:"path"`    // Replaced module path // 被替换的模块路径
+	Target  string `json:"target"`  // Filesystem path as written in go.mod // go.mod 中书写的文件系统路径
+	Resolve string `json:"resolve"` // Absolute DIR of the target // 目标的绝对 DIR
+}
+
+// LocalReplaces returns the filesystem replaces of modules whose target is at or below root
+// Replaces with a module path as target are not local and are left out
+//
+// LocalReplaces 返回 modules 中目标位于 root 或其下方的文件系统 replace
+// 以模块路径为目标的 replace 不是本地的，不包含在内
+func LocalReplaces(modules []Module, root string) []LocalReplace {
+	var res []LocalReplace
+	for _, module := range modules {
+		for _, rep := range module.Replace {
+			if !modfile.IsDirectoryPath(rep.New.Path) {
+				continue
+			}
+			resolve := filepath.FromSlash(rep.New.Path)
+			if !filepath.IsAbs(resolve) {
+				resolve = filepath.Join(module.Path, resolve)
+			}
+			if !isBelow(root, resolve) {
+				continue
+			}
+			res = append(res, LocalReplace{
+				Module:  module.ModulePath,
+				Dir:     module.Path,
+				Path:    rep.Old.Path,
+				Target:  rep.New.Path,
+				Resolve: resolve,
+			})
+		}
+	}
+	return res
+}
+
+// Link computes the change adding a replace of target by its relative DIR to the go.mod of from
+// An existing replace of the target module path is overwritten
+//
+// Link 计算在 from 的 go.mod 中添加 replace 的变更，将 target 替换为其相对 DIR
+// 覆盖该模块路径已有的 replace
+func Link(from Module, target Module) (*Change, error) {
+	rel, err := filepath.Rel(from.Path, target.Path)
+	if err != nil {
+		return nil, err
+	}
+	rel = filepath.ToSlash(rel)
+	if rel != ".." && !strings.HasPrefix(rel, "../") {
+		rel = "./" + rel
+	}
+	return EditGoMod(from.Path, func(modFile *modfile.File) error {
+		return modFile.AddReplace(target.ModulePath, "", rel, "")
+	})
+}
+
+// Unlink computes the change dropping the filesystem replaces of paths from the go.mod of from
+// Every filesystem replace is dropped when paths is empty, replaces with a module target are kept
+//
+// Unlink 计算从 from 的 go.mod 中删除 paths 的文件系统 replace 的变更
+// paths 为空时删除所有文件系统 replace，以模块为目标的 replace 保持不变
+func Unlink(from Module, paths ...string) (*Change, error) {
+	drop := map[string]bool{}
+	for _, path := range paths {
+		drop[path] = true
+	}
+	return EditGoMod(from.Path, func(modFile *modfile.File) error {
+		for _, rep := range modFile.Replace {
+			if !modfile.IsDirectoryPath(rep.New.Path) || (len(paths) > 0 && !drop[rep.Old.Path]) {
+				continue
+			}
+			if err := modFile.DropReplace(rep.Old.Path, rep.Old.Version); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// isBelow reports whether path is root or inside root
+// isBelow 判断 path 是否为 root 或位于 root 之内
+func isBelow(root string, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
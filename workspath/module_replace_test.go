@@ -0,0 +1,49 @@
+package workspath
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+// TestLinkUnlink tests replaces are added with relative DIRs, found as local, and dropped again
+// TestLinkUnlink 测试 replace 以相对 DIR 添加、被识别为本地 replace 并能再次删除
+func TestLinkUnlink(t *testing.T) {
+	tempDIR := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDIR, "api"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDIR, "libs", "core"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDIR, "api", "go.mod"), []byte("module example.com/api\n\ngo 1.22\n\nrequire example.com/core v1.0.0\n\nreplace example.com/remote => example.com/fork v1.0.0\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDIR, "libs", "core", "go.mod"), []byte("module example.com/core\n\ngo 1.22\n"), 0644))
+
+	modules := rese.V1(ScanModules(tempDIR, ScanDeep()))
+	require.Len(t, modules, 2)
+	api, core := modules[0], modules[1]
+	require.Empty(t, LocalReplaces(modules, tempDIR))
+
+	change := rese.P1(Link(api, core))
+	require.Contains(t, string(change.New), "replace example.com/core => ../libs/core\n")
+	require.NoError(t, change.Write())
+
+	change = rese.P1(Link(core, api))
+	require.Contains(t, string(change.New), "replace example.com/api => ../../api\n")
+
+	modules = rese.V1(ScanModules(tempDIR, ScanDeep()))
+	require.Equal(t, []LocalReplace{{
+		Module:  "example.com/api",
+		Dir:     filepath.Join(tempDIR, "api"),
+		Path:    "example.com/core",
+		Target:  "../libs/core",
+		Resolve: filepath.Join(tempDIR, "libs", "core"),
+	}}, LocalReplaces(modules, tempDIR))
+	require.Empty(t, LocalReplaces(modules, filepath.Join(tempDIR, "api")))
+
+	change = rese.P1(Unlink(modules[0], "example.com/other"))
+	require.False(t, change.Changed())
+
+	change = rese.P1(Unlink(modules[0]))
+	require.NotContains(t, string(change.New), "example.com/core =>")
+	require.Contains(t, string(change.New), "replace example.com/remote => example.com/fork v1.0.0\n")
+}
@@ -0,0 +1,140 @@
+package workspath
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-mate/go-work/internal/testfs"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/rese"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// TestScanModules_ParseModFile tests that go.mod directives are parsed into Module
+// TestScanModules_ParseModFile 测试 go.mod 指令被解析到 Module 中
+func TestScanModules_ParseModFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"go.mod": {Data: []byte(`module example.com/root
+
+go 1.22.8
+
+toolchain go1.23.4
+
+godebug default=go1.21
+
+require (
+	example.com/dep v1.2.3
+	example.com/other v0.1.0 // indirect
+)
+
+replace example.com/dep => ./dep
+
+exclude example.com/old v0.0.1
+
+retract v0.9.0 // published too early
+`)},
+		"main.go":    {Data: []byte("package main\n")},
+		"dep/go.mod": {Data: []byte("module example.com/dep\n\ngo 1.21\n")},
+		"bad/go.mod": {Data: []byte("module\n")},
+	}
+
+	modules, err := ScanModules(".", WithFS(fsys), ScanDeep())
+	require.NoError(t, err)
+	t.Log("modules:", neatjsons.S(modules))
+	require.Len(t, modules, 3)
+
+	root := modules[0]
+	require.Equal(t, ".", root.Path)
+	require.Equal(t, ".", root.RelPath)
+	require.Equal(t, "example.com/root", root.ModulePath)
+	require.Equal(t, "1.22.8", root.GoVersion)
+	require.Equal(t, "go1.23.4", root.Toolchain)
+	require.Equal(t, []Godebug{{Key: "default", Value: "go1.21"}}, root.Godebug)
+	require.Equal(t, []Require{
+		{Path: "example.com/dep", Version: "v1.2.3"},
+		{Path: "example.com/other", Version: "v0.1.0", Indirect: true},
+	}, root.Require)
+	require.Equal(t, []Replace{{
+		Old: module.Version{Path: "example.com/dep"},
+		New: module.Version{Path: "./dep"},
+	}}, root.Replace)
+	require.Equal(t, []module.Version{{Path: "example.com/old", Version: "v0.0.1"}}, root.Exclude)
+	require.Equal(t, []Retract{{Low: "v0.9.0", High: "v0.9.0", Rationale: "published too early"}}, root.Retract)
+	require.True(t, root.HasGoFiles)
+	require.NotNil(t, root.File)
+	require.NoError(t, root.Err)
+
+	bad := modules[1]
+	require.Equal(t, "bad", bad.RelPath)
+	require.Error(t, bad.Err)
+	require.Nil(t, bad.File)
+
+	dep := modules[2]
+	require.Equal(t, "dep", dep.RelPath)
+	require.Equal(t, "example.com/dep", dep.ModulePath)
+	require.Equal(t, "1.21", dep.GoVersion)
+	require.False(t, dep.HasGoFiles)
+}
+
+// TestScanModules_RelPath tests relative paths of modules above and below the scan root
+// TestScanModules_RelPath 测试扫描根之上和之下模块的相对路径
+func TestScanModules_RelPath(t *testing.T) {
+	tempDIR := setupTestProject(t)
+	defer cleanupDIR(t, tempDIR)
+
+	scanRoot := filepath.Join(tempDIR, "pkg", "sub")
+	must.Done(os.MkdirAll(scanRoot, 0755))
+	testfs.WriteModule(t, filepath.Join(scanRoot, "inner"), "test/pkg/sub/inner")
+
+	modules, err := ScanModules(scanRoot, WithCurrentProject(), WithCurrentPackage(), ScanDeep())
+	require.NoError(t, err)
+	require.Len(t, modules, 3)
+
+	require.Equal(t, tempDIR, modules[0].Path)
+	require.Equal(t, "../..", modules[0].RelPath)
+	require.Equal(t, "test", modules[0].ModulePath)
+	require.True(t, modules[0].HasGoFiles)
+
+	require.Equal(t, scanRoot, modules[1].Path)
+	require.Equal(t, ".", modules[1].RelPath)
+	require.Empty(t, modules[1].ModulePath)
+	require.False(t, modules[1].HasGoFiles)
+
+	require.Equal(t, "inner", modules[2].RelPath)
+	require.Equal(t, "test/pkg/sub/inner", modules[2].ModulePath)
+}
+
+// TestEditGoMod tests edits keep comments and are only saved by Write
+// TestEditGoMod 测试编辑保留注释且仅在 Write 时保存
+func TestEditGoMod(t *testing.T) {
+	tempDIR := t.TempDir()
+	modPath := filepath.Join(tempDIR, "go.mod")
+	content := "module example.com/a\n\ngo 1.22\n\nrequire (\n\texample.com/dep v1.0.0 // pinned for api\n\texample.com/other v0.1.0 // indirect\n)\n"
+	must.Done(os.WriteFile(modPath, []byte(content), 0644))
+
+	change, err := EditGoMod(tempDIR, func(modFile *modfile.File) error {
+		if err := modFile.AddRequire("example.com/dep", "v1.2.0"); err != nil {
+			return err
+		}
+		return modFile.AddRequire("example.com/other", "v0.2.0")
+	})
+	require.NoError(t, err)
+	require.True(t, change.Changed())
+	require.Equal(t, "module example.com/a\n\ngo 1.22\n\nrequire (\n\texample.com/dep v1.2.0 // pinned for api\n\texample.com/other v0.2.0 // indirect\n)\n", string(change.New))
+	require.Equal(t, content, string(rese.V1(os.ReadFile(modPath))))
+
+	require.NoError(t, change.Write())
+	require.Equal(t, string(change.New), string(rese.V1(os.ReadFile(modPath))))
+
+	change, err = EditGoMod(tempDIR, func(modFile *modfile.File) error { return nil })
+	require.NoError(t, err)
+	require.False(t, change.Changed())
+
+	_, err = EditGoMod(filepath.Join(tempDIR, "missing"), func(modFile *modfile.File) error { return nil })
+	require.Error(t, err)
+}
@@ -1,5 +1,10 @@
 package workspath
 
+import (
+	"io/fs"
+	"runtime"
+)
+
 // scanConfig holds internal scanning configuration
 // scanConfig 保存内部扫描配置
 type scanConfig struct {
@@ -8,6 +13,29 @@ type scanConfig struct {
 	scanDeep       bool // Include submodules // 包含子模块
 	skipNoGo       bool // Skip modules without Go files // 跳过无 Go 文件的模块
 	debugMode      bool // Enable debug logging // 启用调试日志
+
+	continueOnError bool // Skip unreadable paths and report them // 跳过无法读取的路径并报告
+
+	excludes         []string // Glob patterns of paths to skip // 需要跳过的路径 glob 模式
+	respectGitignore bool     // Skip paths ignored by git // 跳过被 git 忽略的路径
+	skipUnderscore   bool     // Skip names starting with _ // 跳过以 _ 开头的名称
+
+	maxDepth     int  // Deepest DIR level to walk, negative means no limit // 遍历的最深 DIR 层级，负数表示不限制
+	stopAtModule bool // Do not descend into found modules // 不进入已找到的模块
+
+	concurrency int // Count of goroutines walking DIRs // 遍历 DIR 的 goroutine 数量
+
+	fsys fs.FS // Filesystem to scan instead of the OS one // 代替操作系统文件系统进行扫描的文件系统
+}
+
+// newScanConfig applies options onto a blank config
+// newScanConfig 将选项应用到空白配置上
+func newScanConfig(opts []Option) *scanConfig {
+	cfg := &scanConfig{maxDepth: -1, concurrency: runtime.NumCPU()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
 }
 
 // Option configures scanning behavior
@@ -49,3 +77,72 @@ func DebugMode() Option {
 func WithDebug(debug bool) Option {
 	return func(c *scanConfig) { c.debugMode = debug }
 }
+
+// ContinueOnError skips unreadable paths and reports them once the scan completes
+// Without it the scan aborts at the first access error
+//
+// ContinueOnError 跳过无法读取的路径并在扫描结束后统一报告
+// 不设置时扫描在首个访问错误处中止
+func ContinueOnError() Option {
+	return func(c *scanConfig) { c.continueOnError = true }
+}
+
+// WithExclude skips paths matching the glob patterns, relative to the scan root
+// Patterns follow gitignore syntax, e.g. "node_modules", "build/", "tools/**/gen"
+//
+// WithExclude 跳过匹配 glob 模式的路径，模式相对于扫描根
+// 模式遵循 gitignore 语法，例如 "node_modules"、"build/"、"tools/**/gen"
+func WithExclude(globs ...string) Option {
+	return func(c *scanConfig) { c.excludes = append(c.excludes, globs...) }
+}
+
+// RespectGitignore skips paths ignored by .gitignore and .git/info/exclude files
+// RespectGitignore 跳过被 .gitignore 和 .git/info/exclude 文件忽略的路径
+func RespectGitignore() Option {
+	return func(c *scanConfig) { c.respectGitignore = true }
+}
+
+// SkipUnderscore skips files and DIRs starting with _, which the go command ignores
+// Off by default, so modules kept in DIRs like _examples are still found
+//
+// SkipUnderscore 跳过以 _ 开头的文件和 DIR，go 命令会忽略它们
+// 默认关闭，因此位于 _examples 这类 DIR 中的模块仍能被找到
+func SkipUnderscore() Option {
+	return func(c *scanConfig) { c.skipUnderscore = true }
+}
+
+// WithMaxDepth limits ScanDeep to DIRs at most n levels below the scan root
+// 0 scans just the root, a negative n means no limit
+//
+// WithMaxDepth 将 ScanDeep 限制在扫描根以下最多 n 层的 DIR
+// 0 仅扫描根目录，负数表示不限制
+func WithMaxDepth(n int) Option {
+	return func(c *scanConfig) { c.maxDepth = n }
+}
+
+// StopAtModule stops ScanDeep from descending into a DIR once it found a go.mod there
+// The scan root itself is always descended
+//
+// StopAtModule 使 ScanDeep 在 DIR 中发现 go.mod 后不再深入该 DIR
+// 扫描根本身总会被深入
+func StopAtModule() Option {
+	return func(c *scanConfig) { c.stopAtModule = true }
+}
+
+// WithConcurrency sets the count of goroutines walking DIRs, defaults to the CPU count
+// Results keep the same order whatever the concurrency, values below 1 mean 1
+//
+// WithConcurrency 设置遍历 DIR 的 goroutine 数量，默认为 CPU 数量
+// 无论并发数多少结果顺序保持一致，小于 1 的值视为 1
+func WithConcurrency(n int) Option {
+	return func(c *scanConfig) { c.concurrency = max(n, 1) }
+}
+
+// WithFS scans fsys instead of the OS filesystem
+// Root and returned paths are then slash separated fs paths, with "." being the FS root
+//
+// WithFS 扫描 fsys 而不是操作系统文件系统
+// 此时根路径和返回的路径均为斜杠分隔的 fs 路径，"." 表示 FS 根
+func WithFS(fsys fs.FS) Option {
+	return func(c *scanConfig) { c.fsys = fsys }
+}
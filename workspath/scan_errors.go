@@ -0,0 +1,48 @@
+package workspath
+
+import (
+	"strings"
+)
+
+// ScanError records a failure to access one path during a scan
+// ScanError 记录扫描过程中访问某个路径时的失败
+type ScanError struct {
+	Path string // Path that could not be accessed // 无法访问的路径
+	Err  error  // Underlying cause // 底层原因
+}
+
+// Error returns the path together with the cause
+// Error 返回路径及其原因
+func (e *ScanError) Error() string {
+	return "scan " + e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying cause
+// Unwrap 返回底层原因
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
+
+// ScanErrors collects the per-path errors of a scan run with ContinueOnError
+// ScanErrors 收集使用 ContinueOnError 扫描时的逐路径错误
+type ScanErrors []*ScanError
+
+// Error joins each path error on its own line
+// Error 将每个路径错误按行拼接
+func (es ScanErrors) Error() string {
+	lines := make([]string, 0, len(es))
+	for _, e := range es {
+		lines = append(lines, e.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap exposes the collected errors to errors.Is and errors.As
+// Unwrap 将收集的错误暴露给 errors.Is 和 errors.As
+func (es ScanErrors) Unwrap() []error {
+	errs := make([]error, 0, len(es))
+	for _, e := range es {
+		errs = append(errs, e)
+	}
+	return errs
+}
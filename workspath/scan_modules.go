@@ -0,0 +1,174 @@
+package workspath
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/emirpasic/gods/v2/sets/linkedhashset"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/zaplog"
+)
+
+// ScanModules detects Go modules starting from root
+// Returns modules based on options, with access errors reported instead of panicking
+// Stops at the first error unless ContinueOnError is set, then returns all errors as ScanErrors
+// Each go.mod is parsed once, parse errors are kept in Module.Err
+//
+// ScanModules 从 root 开始发现 Go 模块
+// 根据选项返回模块，访问错误以返回值报告而不是 panic
+// 默认遇到首个错误即停止，设置 ContinueOnError 时以 ScanErrors 返回全部错误
+// 每个 go.mod 只解析一次，解析错误保存在 Module.Err 中
+func ScanModules(root string, opts ...Option) ([]Module, error) {
+	return ScanModulesContext(context.Background(), root, opts...)
+}
+
+// ScanModulesContext is ScanModules that stops walking once ctx is done
+// Returns ctx.Err() when the scan is cancelled or times out
+//
+// ScanModulesContext 是在 ctx 结束时停止遍历的 ScanModules
+// 扫描被取消或超时时返回 ctx.Err()
+func ScanModulesContext(ctx context.Context, root string, opts ...Option) ([]Module, error) {
+	cfg := newScanConfig(opts)
+	files := newFileSystem(cfg.fsys)
+	scan := &scanner{ctx: ctx, cfg: cfg, files: files, filter: newPathFilter(files, root, cfg)}
+
+	set := linkedhashset.New[string]()
+
+	// WithCurrentProject: find project root and add it
+	// WithCurrentProject: 查找项目根并添加
+	if cfg.currentProject {
+		projectRoot, ok, err := findProjectRoot(ctx, files, root)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			set.Add(projectRoot)
+		}
+	}
+
+	// WithCurrentPackage: add current path itself
+	// WithCurrentPackage: 添加当前路径本身
+	if cfg.currentPackage {
+		set.Add(root)
+	}
+
+	if cfg.debugMode {
+		zaplog.SUG.Debugln("init:", neatjsons.S(set.Values()))
+	}
+
+	// Go files found during the deep walk, so SkipNoGo needs no second pass
+	// 深度遍历时记录的 Go 文件情况，使 SkipNoGo 无需二次遍历
+	hasGoFiles := map[string]bool{}
+
+	if cfg.scanDeep {
+		walk := newWalker(scan)
+		tree := walk.walk(root, 0, walkModules)
+		if err := scan.err(); err != nil {
+			return nil, err
+		}
+		tree.collect(set, hasGoFiles)
+		if cfg.debugMode {
+			zaplog.SUG.Debugln("subs:", neatjsons.S(set.Values()))
+		}
+	}
+
+	if cfg.skipNoGo {
+		set = set.Select(func(idx int, modulePath string) bool {
+			found, ok := hasGoFiles[modulePath]
+			if !ok {
+				found = scan.existsGoFiles(modulePath)
+				hasGoFiles[modulePath] = found
+			}
+			return found
+		})
+		if err := scan.err(); err != nil {
+			return nil, err
+		}
+		if cfg.debugMode {
+			zaplog.SUG.Debugln("skip empty:", neatjsons.S(set.Values()))
+		}
+	}
+
+	modules := make([]Module, 0, set.Size())
+	for _, path := range set.Values() {
+		found, ok := hasGoFiles[path]
+		if !ok {
+			found = scan.existsGoFiles(path)
+		}
+		modules = append(modules, newModule(files, root, path, found))
+	}
+	if err := scan.err(); err != nil {
+		return nil, err
+	}
+	if len(scan.errs) > 0 {
+		sort.SliceStable(scan.errs, func(i, j int) bool {
+			return scan.errs[i].Path < scan.errs[j].Path
+		})
+		return modules, scan.errs
+	}
+	return modules, nil
+}
+
+// scanner carries the config and collected errors of one scan
+// Shared by concurrent walkers, so the errors are guarded by mutex
+//
+// scanner 保存单次扫描的配置和收集到的错误
+// 由并发遍历共享，因此错误由互斥锁保护
+type scanner struct {
+	ctx      context.Context
+	cfg      *scanConfig
+	files    fileSystem
+	filter   *pathFilter
+	mutex    sync.Mutex
+	errs     ScanErrors
+	abortErr error
+}
+
+// fail handles an access error at path
+// Records it when continuing, else keeps the first error to abort the scan
+//
+// fail 处理 path 处的访问错误
+// 继续模式下记录该错误，否则保留首个错误以中止扫描
+func (s *scanner) fail(path string, err error) {
+	scanErr := &ScanError{Path: path, Err: err}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.cfg.continueOnError {
+		s.errs = append(s.errs, scanErr)
+		if s.cfg.debugMode {
+			zaplog.SUG.Debugln("skip:", scanErr.Error())
+		}
+		return
+	}
+	if s.abortErr == nil {
+		s.abortErr = scanErr
+	}
+}
+
+// stopped reports whether the scan is aborted or ctx is done
+// stopped 判断扫描是否已中止或 ctx 已结束
+func (s *scanner) stopped() bool {
+	return s.err() != nil
+}
+
+// err returns ctx.Err() once ctx is done, else the error that aborted the scan
+// err 在 ctx 结束时返回 ctx.Err()，否则返回中止扫描的错误
+func (s *scanner) err() error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.abortErr
+}
+
+// existsGoFiles checks if DIR contains .go source files
+// Nested modules are treated as boundaries and not searched, skipped paths are not counted
+//
+// existsGoFiles 检查 DIR 是否包含 .go 源文件
+// 嵌套模块视为边界，不会被搜索，被跳过的路径不计入
+func (s *scanner) existsGoFiles(root string) bool {
+	node := newWalker(s).walk(root, 0, walkGoFiles)
+	return node != nil && node.hasGoFiles
+}
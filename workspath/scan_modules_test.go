@@ -0,0 +1,172 @@
+package workspath
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-mate/go-work/internal/testfs"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/rese"
+)
+
+// TestScanModules tests module scanning with error return
+// TestScanModules 测试返回错误的模块扫描
+func TestScanModules(t *testing.T) {
+	tempDIR := setupTestProject(t)
+	defer cleanupDIR(t, tempDIR)
+
+	modules, err := ScanModules(tempDIR, WithCurrentProject(), ScanDeep())
+	require.NoError(t, err)
+	t.Log("modules:", neatjsons.S(modules))
+
+	require.Len(t, modules, 2)
+	require.Equal(t, tempDIR, modules[0].Path)
+	require.Equal(t, filepath.Join(tempDIR, "submodule"), modules[1].Path)
+}
+
+// TestScanModules_MissingRoot tests that a missing root is reported instead of panicking
+// TestScanModules_MissingRoot 测试缺失的 root 以错误返回而不是 panic
+func TestScanModules_MissingRoot(t *testing.T) {
+	tempDIR := setupTestProject(t)
+	defer cleanupDIR(t, tempDIR)
+
+	missing := filepath.Join(tempDIR, "missing")
+
+	modules, err := ScanModules(missing, ScanDeep())
+	require.Error(t, err)
+	require.Empty(t, modules)
+	require.True(t, errors.Is(err, fs.ErrNotExist))
+
+	var scanErr *ScanError
+	require.True(t, errors.As(err, &scanErr))
+	require.Equal(t, missing, scanErr.Path)
+}
+
+// TestScanModules_ContinueOnError tests that unreadable DIRs are skipped and reported
+// TestScanModules_ContinueOnError 测试无法读取的 DIR 被跳过并报告
+func TestScanModules_ContinueOnError(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permission bits are not enforced when running as root")
+	}
+
+	tempDIR := setupTestProject(t)
+	defer cleanupDIR(t, tempDIR)
+
+	lockedDIR := filepath.Join(tempDIR, "locked")
+	must.Done(os.MkdirAll(lockedDIR, 0755))
+	must.Done(os.Chmod(lockedDIR, 0000))
+	defer func() {
+		must.Done(os.Chmod(lockedDIR, 0755))
+	}()
+
+	// Default mode aborts at the unreadable DIR
+	_, err := ScanModules(tempDIR, ScanDeep())
+	require.Error(t, err)
+
+	// ContinueOnError keeps the readable modules and reports the locked DIR
+	modules, err := ScanModules(tempDIR, ScanDeep(), ContinueOnError())
+	require.Len(t, modules, 2)
+
+	var scanErrs ScanErrors
+	require.True(t, errors.As(err, &scanErrs))
+	require.Len(t, scanErrs, 1)
+	require.Equal(t, lockedDIR, scanErrs[0].Path)
+}
+
+// TestGetModulePaths_ContinueOnError tests that reported errors do not panic
+// TestGetModulePaths_ContinueOnError 测试报告的错误不会导致 panic
+func TestGetModulePaths_ContinueOnError(t *testing.T) {
+	tempDIR := setupTestProject(t)
+	defer cleanupDIR(t, tempDIR)
+
+	missing := filepath.Join(tempDIR, "missing")
+
+	require.Panics(t, func() {
+		GetModulePaths(missing, ScanDeep())
+	})
+	require.NotPanics(t, func() {
+		paths := GetModulePaths(missing, ScanDeep(), ContinueOnError())
+		require.Empty(t, paths)
+	})
+}
+
+// TestScanModules_WithMaxDepth tests limiting how deep ScanDeep walks
+// TestScanModules_WithMaxDepth 测试限制 ScanDeep 的遍历深度
+func TestScanModules_WithMaxDepth(t *testing.T) {
+	tempDIR := setupNestedProject(t)
+	defer cleanupDIR(t, tempDIR)
+
+	paths := GetModulePaths(tempDIR, ScanDeep())
+	require.Len(t, paths, 4)
+
+	paths = GetModulePaths(tempDIR, ScanDeep(), WithMaxDepth(0))
+	require.Equal(t, []string{tempDIR}, paths)
+
+	paths = GetModulePaths(tempDIR, ScanDeep(), WithMaxDepth(2))
+	require.Equal(t, []string{
+		tempDIR,
+		filepath.Join(tempDIR, "a"),
+		filepath.Join(tempDIR, "a", "b"),
+	}, paths)
+
+	paths = GetModulePaths(tempDIR, ScanDeep(), WithMaxDepth(-1))
+	require.Len(t, paths, 4)
+}
+
+// TestScanModules_StopAtModule tests that found modules are not descended
+// TestScanModules_StopAtModule 测试已找到的模块不会被深入
+func TestScanModules_StopAtModule(t *testing.T) {
+	tempDIR := setupNestedProject(t)
+	defer cleanupDIR(t, tempDIR)
+
+	paths := GetModulePaths(tempDIR, ScanDeep(), StopAtModule())
+	t.Log("paths:", neatjsons.S(paths))
+	require.Equal(t, []string{tempDIR, filepath.Join(tempDIR, "a")}, paths)
+
+	paths = GetModulePaths(filepath.Join(tempDIR, "a", "b"), ScanDeep(), StopAtModule())
+	require.Equal(t, []string{
+		filepath.Join(tempDIR, "a", "b"),
+		filepath.Join(tempDIR, "a", "b", "c"),
+	}, paths)
+}
+
+// TestScanModules_HasGoFilesBeyondLimits tests HasGoFiles of modules whose Go files are below the limits, without SkipNoGo
+// TestScanModules_HasGoFilesBeyondLimits 测试不使用 SkipNoGo 时，Go 文件位于限制以下的模块的 HasGoFiles
+func TestScanModules_HasGoFilesBeyondLimits(t *testing.T) {
+	tempDIR := t.TempDir()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "go.mod"), []byte("module test\n"), 0644))
+	must.Done(os.MkdirAll(filepath.Join(tempDIR, "a", "pkg"), 0755))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a", "go.mod"), []byte("module test/a\n"), 0644))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a", "pkg", "pkg.go"), []byte("package pkg\n"), 0644))
+	must.Done(os.MkdirAll(filepath.Join(tempDIR, "b", "c", "internal"), 0755))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "b", "c", "go.mod"), []byte("module test/b/c\n"), 0644))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "b", "c", "internal", "x.go"), []byte("package internal\n"), 0644))
+
+	hasGoFiles := func(opts ...Option) map[string]bool {
+		res := map[string]bool{}
+		for _, module := range rese.V1(ScanModules(tempDIR, append([]Option{ScanDeep()}, opts...)...)) {
+			res[module.RelPath] = module.HasGoFiles
+		}
+		return res
+	}
+	require.Equal(t, map[string]bool{".": false, "a": true, "b/c": true}, hasGoFiles())
+	require.Equal(t, map[string]bool{".": false, "a": true, "b/c": true}, hasGoFiles(StopAtModule()))
+	require.Equal(t, map[string]bool{".": false, "a": true}, hasGoFiles(WithMaxDepth(1)))
+	require.Equal(t, map[string]bool{".": false, "a": true, "b/c": true}, hasGoFiles(WithMaxDepth(2)))
+}
+
+// setupNestedProject creates modules nested inside each other: root, a, a/b, a/b/c
+// setupNestedProject 创建相互嵌套的模块：root、a、a/b、a/b/c
+func setupNestedProject(t *testing.T) string {
+	tempDIR := rese.V1(os.MkdirTemp("", "test-nested-*"))
+	testfs.WriteModule(t, tempDIR, "test")
+	testfs.WriteModule(t, filepath.Join(tempDIR, "a"), "test/a")
+	testfs.WriteModule(t, filepath.Join(tempDIR, "a", "b"), "test/a/b")
+	testfs.WriteModule(t, filepath.Join(tempDIR, "a", "b", "c"), "test/a/b/c")
+	return tempDIR
+}
@@ -0,0 +1,193 @@
+package workspath
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Package describes a Go package found by ScanPackages
+// Package 描述 ScanPackages 找到的 Go 包
+type Package struct {
+	ImportPath  string   `json:"importPath"`  // Module path joined with the DIR from the module root // 模块路径与相对模块根的 DIR 拼接而成
+	Dir         string   `json:"dir"`         // DIR holding the Go files // 包含 Go 文件的 DIR
+	Name        string   `json:"name"`        // Package name of the non-test files, of the test files when test-only // 非测试文件的包名，仅有测试时为测试文件的包名
+	IsMain      bool     `json:"isMain"`      // Package name is main // 包名为 main
+	TestOnly    bool     `json:"testOnly"`    // Just _test.go files // 只有 _test.go 文件
+	HasCgo      bool     `json:"hasCgo"`      // Non-test files import "C" // 非测试文件导入了 "C"
+	GoFiles     []string `json:"goFiles"`     // Non-test .go file names // 非测试 .go 文件名
+	TestGoFiles []string `json:"testGoFiles"` // _test.go file names // _test.go 文件名
+	Imports     []string `json:"imports"`     // Sorted distinct imports of the non-test files // 非测试文件去重并排序后的导入
+	TestImports []string `json:"testImports"` // Sorted distinct imports of the _test.go files // _test.go 文件去重并排序后的导入
+}
+
+// ScanPackages lists the packages of module in pre-order without invoking the go command
+// Nested modules are boundaries like in existsGoFiles, and the scan filters apply with module.Path as root
+// Paths are skipped like in ScanModules, names starting with _ always, build constraints are evaluated for the current platform like go list does
+//
+// ScanPackages 以先序列出 module 中的包，不调用 go 命令
+// 与 existsGoFiles 一样将嵌套模块视为边界，扫描过滤条件以 module.Path 为根生效
+// 与 ScanModules 一样跳过路径，以 _ 开头的名称总是跳过，与 go list 一样按当前平台计算构建约束
+func ScanPackages(module Module, opts ...Option) ([]Package, error) {
+	return ScanPackagesContext(context.Background(), module, opts...)
+}
+
+// ScanPackagesContext is ScanPackages that stops walking once ctx is done
+// ScanPackagesContext 是在 ctx 结束时停止遍历的 ScanPackages
+func ScanPackagesContext(ctx context.Context, module Module, opts ...Option) ([]Package, error) {
+	cfg := newScanConfig(opts)
+	// The go command never builds packages in DIRs starting with _, so SkipUnderscore is implied here
+	// go 命令从不构建以 _ 开头的 DIR 中的包，因此这里隐含 SkipUnderscore
+	cfg.skipUnderscore = true
+	files := newFileSystem(cfg.fsys)
+	scan := &scanner{ctx: ctx, cfg: cfg, files: files, filter: newPathFilter(files, module.Path, cfg)}
+
+	var packages []Package
+	scan.walkPackages(module, module.Path, 0, &packages)
+	if err := scan.err(); err != nil {
+		return nil, err
+	}
+	if len(scan.errs) > 0 {
+		sort.SliceStable(scan.errs, func(i, j int) bool {
+			return scan.errs[i].Path < scan.errs[j].Path
+		})
+		return packages, scan.errs
+	}
+	return packages, nil
+}
+
+// walkPackages adds the package in DIR path, then walks its sub DIRs, stopping at nested modules
+// Lists DIRs through readDIR like the module walker, so excludes, gitignore rules and default skips match
+//
+// walkPackages 添加 DIR path 中的包，然后遍历其子 DIR，在嵌套模块处停止
+// 与模块遍历一样通过 readDIR 列出 DIR，使排除项、gitignore 规则和默认跳过保持一致
+func (s *scanner) walkPackages(module Module, path string, depth int, packages *[]Package) {
+	if s.stopped() {
+		return
+	}
+	listing, ok := s.readDIR(path)
+	if !ok || depth > 0 && listing.isModule {
+		return
+	}
+	if len(listing.goFiles) > 0 {
+		if pkg, ok := s.readPackage(module, path, listing.goFiles); ok {
+			*packages = append(*packages, pkg)
+		}
+	}
+	for _, subPath := range listing.subDIRs {
+		s.walkPackages(module, subPath, depth+1, packages)
+	}
+}
+
+// readPackage parses the package clause and imports of the Go files in DIR path
+// Files excluded by build constraints on the current platform are left out, like a //go:build ignore generator
+// Files declaring different packages make the DIR fail like in the go command
+//
+// readPackage 解析 DIR path 中 Go 文件的 package 子句和导入
+// 在当前平台被构建约束排除的文件不计入，例如 //go:build ignore 的生成器
+// 文件声明不同的包时该 DIR 失败，与 go 命令一致
+func (s *scanner) readPackage(module Module, path string, goFiles []string) (Package, bool) {
+	pkg := Package{Dir: path, GoFiles: []string{}, TestGoFiles: []string{}}
+	imports, testImports := map[string]bool{}, map[string]bool{}
+	rel, _ := s.files.Rel(module.Path, path)
+	pkg.ImportPath = module.ModulePath
+	if rel != "." && rel != "" {
+		pkg.ImportPath += "/" + rel
+	}
+
+	// The build context reads the content already loaded, so WithFS works and each file is read once
+	// 构建上下文读取已加载的内容，因此 WithFS 可用且每个文件只读取一次
+	var content []byte
+	ctxt := build.Default
+	ctxt.JoinPath = s.files.Join
+	ctxt.OpenFile = func(string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(content)), nil
+	}
+
+	var nameFile, testName, testFile string
+	fset := token.NewFileSet()
+	for _, name := range goFiles {
+		filePath := s.files.Join(path, name)
+		var err error
+		if content, err = s.files.ReadFile(filePath); err != nil {
+			s.fail(filePath, err)
+			return Package{}, false
+		}
+		match, err := ctxt.MatchFile(path, name)
+		if err != nil {
+			s.fail(filePath, err)
+			return Package{}, false
+		}
+		if !match {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filePath, content, parser.ImportsOnly)
+		if err != nil {
+			s.fail(filePath, err)
+			return Package{}, false
+		}
+		if strings.HasSuffix(name, "_test.go") {
+			pkg.TestGoFiles = append(pkg.TestGoFiles, name)
+			addImports(testImports, file)
+			if testName == "" {
+				testName, testFile = strings.TrimSuffix(file.Name.Name, "_test"), name
+			} else if base := strings.TrimSuffix(file.Name.Name, "_test"); base != testName {
+				s.fail(path, fmt.Errorf("found packages %s (%s) and %s (%s)", testName, testFile, base, name))
+				return Package{}, false
+			}
+			continue
+		}
+		pkg.GoFiles = append(pkg.GoFiles, name)
+		if pkg.Name == "" {
+			pkg.Name, nameFile = file.Name.Name, name
+		} else if file.Name.Name != pkg.Name {
+			s.fail(path, fmt.Errorf("found packages %s (%s) and %s (%s)", pkg.Name, nameFile, file.Name.Name, name))
+			return Package{}, false
+		}
+		addImports(imports, file)
+	}
+	if len(pkg.GoFiles) == 0 && len(pkg.TestGoFiles) == 0 {
+		return Package{}, false
+	}
+	if len(pkg.GoFiles) > 0 && testName != "" && testName != pkg.Name {
+		s.fail(path, fmt.Errorf("found packages %s (%s) and %s (%s)", pkg.Name, nameFile, testName, testFile))
+		return Package{}, false
+	}
+	pkg.HasCgo = imports["C"]
+	pkg.Imports, pkg.TestImports = sortedKeys(imports), sortedKeys(testImports)
+	if len(pkg.GoFiles) == 0 {
+		pkg.TestOnly = true
+		pkg.Name = testName
+	}
+	pkg.IsMain = pkg.Name == "main"
+	return pkg, true
+}
+
+// addImports adds the import paths of file to set
+// addImports 将 file 的导入路径添加到 set
+func addImports(set map[string]bool, file *ast.File) {
+	for _, spec := range file.Imports {
+		if importPath, err := strconv.Unquote(spec.Path.Value); err == nil {
+			set[importPath] = true
+		}
+	}
+}
+
+// sortedKeys returns the keys of set in sorted order, empty but not nil when set is empty
+// sortedKeys 返回 set 中排序后的键，set 为空时返回空而非 nil 的切片
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,84 @@
+package workspath
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+// TestScanPackages tests packages are listed per DIR with nested modules, hidden, excluded and gitignored DIRs left out
+// TestScanPackages 测试按 DIR 列出包，且不包含嵌套模块以及隐藏、排除和 gitignore 忽略的 DIR
+func TestScanPackages(t *testing.T) {
+	fsys := fstest.MapFS{
+		"go.mod":                   {Data: []byte("module example.com/root\n\ngo 1.22\n")},
+		"root.go":                  {Data: []byte("package root\n")},
+		"root_test.go":             {Data: []byte("package root_test\n")},
+		"cmd/app/main.go":          {Data: []byte("package main\n\nimport \"fmt\"\n")},
+		"native/native.go":         {Data: []byte("package native\n\n// #include <stdio.h>\nimport \"C\"\n")},
+		"native/native_test.go":    {Data: []byte("package native\n\nimport \"C\"\n")},
+		"e2e/e2e_test.go":          {Data: []byte("package e2e_test\n\nimport \"testing\"\n")},
+		"docs/readme.md":           {Data: []byte("# docs\n")},
+		"sub/go.mod":               {Data: []byte("module example.com/sub\n")},
+		"sub/sub.go":               {Data: []byte("package sub\n")},
+		"_old/old.go":              {Data: []byte("package old\n")},
+		"testdata/fixture/main.go": {Data: []byte("package main\n")},
+		"vendor/x/x.go":            {Data: []byte("package x\n")},
+		"skip/skip.go":             {Data: []byte("package skip\n")},
+		".hidden/hidden.go":        {Data: []byte("package hidden\n")},
+		".gitignore":               {Data: []byte("gen/\n")},
+		"gen/gen.go":               {Data: []byte("package gen\n")},
+		"lib/lib.go":               {Data: []byte("package lib\n")},
+		"lib/generate.go":          {Data: []byte("//go:build ignore\n\npackage main\n\nimport \"os\"\n")},
+	}
+	module := Module{Path: ".", ModulePath: "example.com/root"}
+
+	packages := rese.V1(ScanPackages(module, WithFS(fsys), WithExclude("skip"), RespectGitignore()))
+	require.Equal(t, []Package{
+		{ImportPath: "example.com/root", Dir: ".", Name: "root", GoFiles: []string{"root.go"}, TestGoFiles: []string{"root_test.go"}, Imports: []string{}, TestImports: []string{}},
+		{ImportPath: "example.com/root/cmd/app", Dir: "cmd/app", Name: "main", IsMain: true, GoFiles: []string{"main.go"}, TestGoFiles: []string{}, Imports: []string{"fmt"}, TestImports: []string{}},
+		{ImportPath: "example.com/root/e2e", Dir: "e2e", Name: "e2e", TestOnly: true, GoFiles: []string{}, TestGoFiles: []string{"e2e_test.go"}, Imports: []string{}, TestImports: []string{"testing"}},
+		{ImportPath: "example.com/root/lib", Dir: "lib", Name: "lib", GoFiles: []string{"lib.go"}, TestGoFiles: []string{}, Imports: []string{}, TestImports: []string{}},
+		{ImportPath: "example.com/root/native", Dir: "native", Name: "native", HasCgo: true, GoFiles: []string{"native.go"}, TestGoFiles: []string{"native_test.go"}, Imports: []string{"C"}, TestImports: []string{"C"}},
+	}, packages)
+
+	// Without the gitignore rules gen is listed like in ScanModules
+	// 不使用 gitignore 规则时 gen 与 ScanModules 中一样被列出
+	packages = rese.V1(ScanPackages(module, WithFS(fsys), WithExclude("skip")))
+	require.Len(t, packages, 6)
+	require.Equal(t, "example.com/root/gen", packages[3].ImportPath)
+
+	sub := rese.V1(ScanPackages(Module{Path: "sub", ModulePath: "example.com/sub"}, WithFS(fsys)))
+	require.Len(t, sub, 1)
+	require.Equal(t, "example.com/sub", sub[0].ImportPath)
+
+	fsys["bad/bad.go"] = &fstest.MapFile{Data: []byte("not go\n")}
+	_, err := ScanPackages(module, WithFS(fsys))
+	require.Error(t, err)
+	packages, err = ScanPackages(module, WithFS(fsys), ContinueOnError())
+	require.Error(t, err)
+	require.Len(t, packages, 7)
+}
+
+// TestScanPackages_PackageMismatch tests files declaring different packages fail their DIR
+// TestScanPackages_PackageMismatch 测试声明不同包的文件使其所在 DIR 失败
+func TestScanPackages_PackageMismatch(t *testing.T) {
+	fsys := fstest.MapFS{
+		"go.mod":       {Data: []byte("module example.com/root\n\ngo 1.22\n")},
+		"a/a.go":       {Data: []byte("package a\n")},
+		"a/b.go":       {Data: []byte("package b\n")},
+		"c/c.go":       {Data: []byte("package c\n")},
+		"c/c_test.go":  {Data: []byte("package d_test\n")},
+		"ok/ok.go":     {Data: []byte("package ok\n")},
+		"ok/x_test.go": {Data: []byte("package ok_test\n")},
+	}
+	packages, err := ScanPackages(Module{Path: ".", ModulePath: "example.com/root"}, WithFS(fsys), ContinueOnError())
+	var scanErrs ScanErrors
+	require.ErrorAs(t, err, &scanErrs)
+	require.Len(t, scanErrs, 2)
+	require.ErrorContains(t, scanErrs[0], "found packages a (a.go) and b (b.go)")
+	require.ErrorContains(t, scanErrs[1], "found packages c (c.go) and d (c_test.go)")
+	require.Len(t, packages, 1)
+	require.Equal(t, "example.com/root/ok", packages[0].ImportPath)
+}
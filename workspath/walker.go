@@ -0,0 +1,202 @@
+package workspath
+
+import (
+	"path/filepath"
+	"sync"
+
+	"github.com/emirpasic/gods/v2/sets/linkedhashset"
+)
+
+// walkMode selects what a walk looks for
+// walkMode 选择遍历查找的内容
+type walkMode int
+
+const (
+	walkModules walkMode = iota // Find go.mod DIRs and Go files in one pass // 一次遍历同时查找 go.mod DIR 和 Go 文件
+	walkGoFiles                 // Just find Go files, stop at nested modules // 仅查找 Go 文件，在嵌套模块处停止
+)
+
+// dirNode is the walk result of one DIR
+// dirNode 是单个 DIR 的遍历结果
+type dirNode struct {
+	path       string     // DIR path // DIR 路径
+	isModule   bool       // DIR contains go.mod // DIR 包含 go.mod
+	hasGoFiles bool       // DIR contains .go files outside nested modules // DIR 在嵌套模块之外包含 .go 文件
+	mode       walkMode   // Mode the DIR was walked in // 遍历该 DIR 时的模式
+	children   []*dirNode // Walked sub DIRs in name order, nil when not accessible // 按名称排序的子 DIR，无法访问时为 nil
+}
+
+// dirListing is the content of one DIR left after the scan filter
+// dirListing 是单个 DIR 经扫描过滤后剩余的内容
+type dirListing struct {
+	subDIRs  []string // Sub DIR paths in name order // 按名称排序的子 DIR 路径
+	goFiles  []string // Names of the .go files // .go 文件名称
+	isModule bool     // DIR contains go.mod // DIR 包含 go.mod
+}
+
+// readDIR lists DIR path through the scan filter, shared by module and package scans so both skip the same paths
+// readDIR 经扫描过滤列出 DIR path，由模块扫描和包扫描共用，使两者跳过相同的路径
+func (s *scanner) readDIR(path string) (*dirListing, bool) {
+	entries, err := s.files.ReadDir(path)
+	if err != nil {
+		s.fail(path, err)
+		return nil, false
+	}
+	listing := &dirListing{}
+	for _, entry := range entries {
+		subPath := s.files.Join(path, entry.Name())
+		skip, err := s.filter.skip(subPath, entry.IsDir())
+		if err != nil {
+			s.fail(subPath, err)
+			continue
+		}
+		if skip {
+			continue
+		}
+		if entry.IsDir() {
+			listing.subDIRs = append(listing.subDIRs, subPath)
+		} else if entry.Name() == "go.mod" {
+			listing.isModule = true
+		} else if filepath.Ext(entry.Name()) == ".go" {
+			listing.goFiles = append(listing.goFiles, entry.Name())
+		}
+	}
+	return listing, true
+}
+
+// walker walks DIR trees using a bounded count of goroutines
+// Sub DIRs run in fresh goroutines while slots are free, else inline in the calling goroutine
+//
+// walker 使用有限数量的 goroutine 遍历 DIR 树
+// 有空闲名额时子 DIR 在新 goroutine 中运行，否则在调用方 goroutine 中内联运行
+type walker struct {
+	scan  *scanner
+	slots chan struct{}
+}
+
+// newWalker creates a walker that respects the concurrency of the scan config
+// newWalker 创建遵循扫描配置并发数的 walker
+func newWalker(scan *scanner) *walker {
+	return &walker{
+		scan:  scan,
+		slots: make(chan struct{}, max(scan.cfg.concurrency-1, 0)),
+	}
+}
+
+// walk reads DIR at depth below the scan root and returns its node, nil when not accessible
+// walk 读取扫描根以下 depth 层的 DIR 并返回其节点，无法访问时返回 nil
+func (w *walker) walk(path string, depth int, mode walkMode) *dirNode {
+	if w.scan.stopped() {
+		return nil
+	}
+	listing, ok := w.scan.readDIR(path)
+	if !ok {
+		return nil
+	}
+	node := &dirNode{path: path, isModule: listing.isModule, hasGoFiles: len(listing.goFiles) > 0, mode: mode}
+	subDIRs := listing.subDIRs
+
+	// A nested module is a boundary when just looking for Go files
+	// 仅查找 Go 文件时嵌套模块是边界
+	if mode == walkGoFiles && (depth > 0 && node.isModule || node.hasGoFiles) {
+		return node
+	}
+
+	// Sub DIRs not walked for modules are still searched for Go files, so Module.HasGoFiles holds in every mode
+	// 未按模块遍历的子 DIR 仍会查找 Go 文件，使 Module.HasGoFiles 在任何模式下都成立
+	if w.subMode(node, depth) == walkModules {
+		node.children = w.walkAll(subDIRs, depth+1)
+	} else {
+		node.children = w.walkEach(subDIRs, depth+1, node)
+	}
+	for _, child := range node.children {
+		if child != nil && !child.isModule && child.hasGoFiles {
+			node.hasGoFiles = true
+		}
+	}
+	return node
+}
+
+// subMode decides how the sub DIRs of node are walked
+// Beyond the max depth or inside a stopped module just the Go files still matter
+//
+// subMode 决定 node 的子 DIR 如何遍历
+// 超出最大深度或在停止的模块内部时只关心 Go 文件
+func (w *walker) subMode(node *dirNode, depth int) walkMode {
+	cfg := w.scan.cfg
+	switch {
+	case node.mode == walkGoFiles:
+		return walkGoFiles
+	case cfg.maxDepth >= 0 && depth+1 > cfg.maxDepth:
+		return walkGoFiles
+	case cfg.stopAtModule && depth > 0 && node.isModule:
+		return walkGoFiles
+	default:
+		return walkModules
+	}
+}
+
+// walkAll walks the sub DIRs concurrently, keeping results in name order
+// walkAll 并发遍历子 DIR，结果保持名称顺序
+func (w *walker) walkAll(subDIRs []string, depth int) []*dirNode {
+	children := make([]*dirNode, len(subDIRs))
+	var wg sync.WaitGroup
+	for idx, subPath := range subDIRs {
+		select {
+		case w.slots <- struct{}{}:
+			wg.Add(1)
+			go func(idx int, subPath string) {
+				defer wg.Done()
+				defer func() { <-w.slots }()
+				children[idx] = w.walk(subPath, depth, walkModules)
+			}(idx, subPath)
+		default:
+			children[idx] = w.walk(subPath, depth, walkModules)
+		}
+	}
+	wg.Wait()
+	return children
+}
+
+// walkEach walks the sub DIRs one by one looking for Go files, stops at the first hit
+// walkEach 逐个遍历子 DIR 查找 Go 文件，首次命中即停止
+func (w *walker) walkEach(subDIRs []string, depth int, parent *dirNode) []*dirNode {
+	var children []*dirNode
+	for _, subPath := range subDIRs {
+		if parent.hasGoFiles {
+			break
+		}
+		child := w.walk(subPath, depth, walkGoFiles)
+		children = append(children, child)
+		if child != nil && !child.isModule && child.hasGoFiles {
+			break
+		}
+	}
+	return children
+}
+
+// collect adds the modules in the tree to set in pre-order and records whether each has Go files
+// The tree root is recorded even when it is not a module, matching WithCurrentPackage
+//
+// collect 以先序将树中的模块添加到 set 并记录每个模块是否有 Go 文件
+// 树根即使不是模块也会被记录，以配合 WithCurrentPackage
+func (node *dirNode) collect(set *linkedhashset.Set[string], hasGoFiles map[string]bool) {
+	if node == nil {
+		return
+	}
+	hasGoFiles[node.path] = node.hasGoFiles
+	var visit func(node *dirNode)
+	visit = func(node *dirNode) {
+		if node == nil || node.mode != walkModules {
+			return
+		}
+		if node.isModule {
+			set.Add(node.path)
+			hasGoFiles[node.path] = node.hasGoFiles
+		}
+		for _, child := range node.children {
+			visit(child)
+		}
+	}
+	visit(node)
+}
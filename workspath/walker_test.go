@@ -0,0 +1,68 @@
+package workspath
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-mate/go-work/internal/testfs"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestScanModules_WithConcurrency tests that results keep the same order at any concurrency
+// TestScanModules_WithConcurrency 测试任意并发数下结果顺序保持一致
+func TestScanModules_WithConcurrency(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "test-walker-*"))
+	defer cleanupDIR(t, tempDIR)
+
+	testfs.WriteModule(t, tempDIR, "test")
+	for idx := 0; idx < 8; idx++ {
+		for sub := 0; sub < 4; sub++ {
+			dir := filepath.Join(tempDIR, fmt.Sprintf("group%d", idx), fmt.Sprintf("mod%d", sub))
+			testfs.WriteModule(t, dir, fmt.Sprintf("test/group%d/mod%d", idx, sub))
+		}
+	}
+
+	expected := GetModulePaths(tempDIR, ScanDeep(), SkipNoGo(), WithConcurrency(1))
+	require.Len(t, expected, 33)
+	require.Equal(t, tempDIR, expected[0])
+	require.Equal(t, filepath.Join(tempDIR, "group0", "mod0"), expected[1])
+
+	for _, concurrency := range []int{0, 2, 16} {
+		paths := GetModulePaths(tempDIR, ScanDeep(), SkipNoGo(), WithConcurrency(concurrency))
+		require.Equal(t, expected, paths, "concurrency=%d", concurrency)
+	}
+}
+
+// TestScanModules_SkipNoGoBeyondLimits tests Go file detection below the max depth and inside stopped modules
+// TestScanModules_SkipNoGoBeyondLimits 测试在最大深度以下和停止的模块内部检测 Go 文件
+func TestScanModules_SkipNoGoBeyondLimits(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "test-walker-*"))
+	defer cleanupDIR(t, tempDIR)
+
+	// Module whose Go files live deep below its root
+	// Go 文件位于根目录深处的模块
+	deepDIR := filepath.Join(tempDIR, "deep")
+	must.Done(os.MkdirAll(filepath.Join(deepDIR, "a", "b", "c"), 0755))
+	must.Done(os.WriteFile(filepath.Join(deepDIR, "go.mod"), []byte("module deep\n"), 0644))
+	must.Done(os.WriteFile(filepath.Join(deepDIR, "a", "b", "c", "c.go"), []byte("package c\n"), 0644))
+
+	// Module whose only Go files belong to a nested module
+	// 仅有的 Go 文件属于嵌套模块的模块
+	shellDIR := filepath.Join(tempDIR, "shell")
+	must.Done(os.MkdirAll(shellDIR, 0755))
+	must.Done(os.WriteFile(filepath.Join(shellDIR, "go.mod"), []byte("module shell\n"), 0644))
+	testfs.WriteModule(t, filepath.Join(shellDIR, "inner"), "shell/inner")
+
+	paths := GetModulePaths(tempDIR, ScanDeep(), SkipNoGo())
+	require.Equal(t, []string{deepDIR, filepath.Join(shellDIR, "inner")}, paths)
+
+	paths = GetModulePaths(tempDIR, ScanDeep(), SkipNoGo(), WithMaxDepth(1))
+	require.Equal(t, []string{deepDIR}, paths)
+
+	paths = GetModulePaths(tempDIR, ScanDeep(), SkipNoGo(), StopAtModule())
+	require.Equal(t, []string{deepDIR}, paths)
+}
@@ -6,15 +6,11 @@
 package workspath
 
 import (
+	"context"
+	"errors"
 	"io/fs"
-	"path/filepath"
-	"strings"
 
-	"github.com/emirpasic/gods/v2/sets/linkedhashset"
 	"github.com/yyle88/must"
-	"github.com/yyle88/neatjson/neatjsons"
-	"github.com/yyle88/osexistpath/osmustexist"
-	"github.com/yyle88/osexistpath/osomitexist"
 	"github.com/yyle88/zaplog"
 )
 
@@ -31,16 +27,54 @@ type ProjectPath struct {
 // GetProjectPath 通过向上遍历 DIR 树定位 Go 项目根目录
 // 返回包含项目根路径和中间路径的 ProjectPath
 func GetProjectPath(path string) (*ProjectPath, bool) {
+	return findProjectPath(osFileSystem{}, path)
+}
+
+// GetProjectPathFS is GetProjectPath on fsys, using slash separated fs paths
+// GetProjectPathFS 是作用于 fsys 的 GetProjectPath，使用斜杠分隔的 fs 路径
+func GetProjectPathFS(fsys fs.FS, path string) (*ProjectPath, bool) {
+	return findProjectPath(fsFileSystem{fsys: fsys}, path)
+}
+
+// GetProjectRoot locates Go project root by traversing up the DIR tree
+// Returns project root path and true if go.mod is found
+//
+// GetProjectRoot 通过向上遍历 DIR 树定位 Go 项目根目录
+// 找到 go.mod 时返回项目根路径和 true
+func GetProjectRoot(path string) (string, bool) {
+	root, ok, _ := findProjectRoot(context.Background(), osFileSystem{}, path)
+	return root, ok
+}
+
+// GetProjectRootFS is GetProjectRoot on fsys, using slash separated fs paths
+// GetProjectRootFS 是作用于 fsys 的 GetProjectRoot，使用斜杠分隔的 fs 路径
+func GetProjectRootFS(fsys fs.FS, path string) (string, bool) {
+	root, ok, _ := findProjectRoot(context.Background(), fsFileSystem{fsys: fsys}, path)
+	return root, ok
+}
+
+// GetProjectRootContext is GetProjectRoot that stops traversing once ctx is done
+// Returns ctx.Err() when cancelled before go.mod is found
+//
+// GetProjectRootContext 是在 ctx 结束时停止遍历的 GetProjectRoot
+// 在找到 go.mod 之前被取消时返回 ctx.Err()
+func GetProjectRootContext(ctx context.Context, path string) (string, bool, error) {
+	return findProjectRoot(ctx, osFileSystem{}, path)
+}
+
+// findProjectPath traverses up from path to the DIR containing go.mod, collecting the middle path
+// findProjectPath 从 path 向上遍历到包含 go.mod 的 DIR，并收集中间路径
+func findProjectPath(files fileSystem, path string) (*ProjectPath, bool) {
 	root := path
 	subs := ""
-	for !osomitexist.IsFile(filepath.Join(root, "go.mod")) {
-		subName := filepath.Base(root)
-		parent := filepath.Dir(root)
+	for !files.IsFile(files.Join(root, "go.mod")) {
+		subName := files.Base(root)
+		parent := files.Dir(root)
 		if parent == root {
 			return nil, false
 		}
 		root = parent
-		subs = filepath.Join(subName, subs)
+		subs = files.Join(subName, subs)
 	}
 	return &ProjectPath{
 		Root:    root,
@@ -48,112 +82,58 @@ func GetProjectPath(path string) (*ProjectPath, bool) {
 	}, true
 }
 
-// GetProjectRoot locates Go project root by traversing up the DIR tree
-// Returns project root path and true if go.mod is found
-//
-// GetProjectRoot 通过向上遍历 DIR 树定位 Go 项目根目录
-// 找到 go.mod 时返回项目根路径和 true
-func GetProjectRoot(path string) (string, bool) {
+// findProjectRoot traverses up from path to the DIR containing go.mod, checking ctx at each step
+// findProjectRoot 从 path 向上遍历到包含 go.mod 的 DIR，每一步检查 ctx
+func findProjectRoot(ctx context.Context, files fileSystem, path string) (string, bool, error) {
 	root := path
-	for !osomitexist.IsFile(filepath.Join(root, "go.mod")) {
-		parent := filepath.Dir(root)
+	for !files.IsFile(files.Join(root, "go.mod")) {
+		if err := ctx.Err(); err != nil {
+			return "", false, err
+		}
+		parent := files.Dir(root)
 		if parent == root {
-			return "", false
+			return "", false, nil
 		}
 		root = parent
 	}
-	return root, true
+	return root, true, nil
 }
 
 // GetModulePaths detects Go module paths starting from path
 // Returns slice of module paths based on options
+// Panics on access errors, unless ContinueOnError is set, then they are logged
 //
 // GetModulePaths 从 path 开始发现 Go 模块路径
 // 根据选项返回模块路径切片
+// 遇到访问错误时 panic，设置 ContinueOnError 时仅记录日志
 func GetModulePaths(root string, opts ...Option) []string {
-	cfg := &scanConfig{}
-	for _, opt := range opts {
-		opt(cfg)
-	}
-
-	set := linkedhashset.New[string]()
-
-	// WithCurrentProject: find project root and add it
-	// WithCurrentProject: 查找项目根并添加
-	if cfg.currentProject {
-		if projectRoot, ok := GetProjectRoot(root); ok {
-			set.Add(projectRoot)
+	modules, err := ScanModules(root, opts...)
+	if err != nil {
+		var scanErrs ScanErrors
+		if !errors.As(err, &scanErrs) {
+			must.Done(err)
 		}
+		zaplog.SUG.Warnln("scan errors:", scanErrs.Error())
 	}
-
-	// WithCurrentPackage: add current path itself
-	// WithCurrentPackage: 添加当前路径本身
-	if cfg.currentPackage {
-		set.Add(root)
-	}
-
-	if cfg.debugMode {
-		zaplog.SUG.Debugln("init:", neatjsons.S(set.Values()))
-	}
-
-	if cfg.scanDeep {
-		must.Done(filepath.Walk(root, func(path string, info fs.FileInfo, err error) error {
-			if isHidden(info) {
-				if info.IsDir() {
-					return filepath.SkipDir
-				}
-				return nil
-			}
-			if !info.IsDir() && info.Name() == "go.mod" {
-				if moduleRoot := filepath.Dir(path); osmustexist.IsRoot(moduleRoot) {
-					set.Add(moduleRoot)
-				}
-			}
-			return nil
-		}))
-		if cfg.debugMode {
-			zaplog.SUG.Debugln("subs:", neatjsons.S(set.Values()))
-		}
-	}
-
-	if cfg.skipNoGo {
-		set = set.Select(func(idx int, modulePath string) bool {
-			return existsGoFiles(modulePath)
-		})
-		if cfg.debugMode {
-			zaplog.SUG.Debugln("skip empty:", neatjsons.S(set.Values()))
-		}
-	}
-
-	return set.Values()
+	return modulePaths(modules)
 }
 
-// isHidden checks if path should be skipped (hidden files/dirs)
-// isHidden 检查是否应跳过路径（隐藏文件/目录）
-func isHidden(info fs.FileInfo) bool {
-	return strings.HasPrefix(info.Name(), ".")
+// GetModulePathsContext is GetModulePaths that stops walking once ctx is done
+// Returns errors instead of panicking, ctx.Err() when the scan is cancelled or times out
+//
+// GetModulePathsContext 是在 ctx 结束时停止遍历的 GetModulePaths
+// 以返回值报告错误而不是 panic，扫描被取消或超时时返回 ctx.Err()
+func GetModulePathsContext(ctx context.Context, root string, opts ...Option) ([]string, error) {
+	modules, err := ScanModulesContext(ctx, root, opts...)
+	return modulePaths(modules), err
 }
 
-// existsGoFiles checks if DIR contains .go source files
-// existsGoFiles 检查 DIR 是否包含 .go 源文件
-func existsGoFiles(root string) bool {
-	found := false
-	must.Done(filepath.Walk(root, func(path string, info fs.FileInfo, err error) error {
-		if isHidden(info) {
-			if info.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-		if info.IsDir() {
-			if path != root && osomitexist.IsFile(filepath.Join(path, "go.mod")) {
-				return filepath.SkipDir
-			}
-		} else if filepath.Ext(info.Name()) == ".go" {
-			found = true
-			return filepath.SkipAll
-		}
-		return nil
-	}))
-	return found
+// modulePaths extracts the DIR of each module
+// modulePaths 提取每个模块的 DIR
+func modulePaths(modules []Module) []string {
+	paths := make([]string, 0, len(modules))
+	for _, module := range modules {
+		paths = append(paths, module.Path)
+	}
+	return paths
 }
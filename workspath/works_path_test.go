@@ -6,9 +6,11 @@
 package workspath
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/yyle88/must"
@@ -145,6 +147,47 @@ func TestGetModulePaths_WithCurrentPackage(t *testing.T) {
 	require.Contains(t, paths, pkgPath)
 }
 
+// TestGetModulePathsContext tests context-aware module scanning
+// TestGetModulePathsContext 测试支持 context 的模块扫描
+func TestGetModulePathsContext(t *testing.T) {
+	tempDIR := setupTestProject(t)
+	defer cleanupDIR(t, tempDIR)
+
+	paths, err := GetModulePathsContext(context.Background(), tempDIR, WithCurrentProject(), ScanDeep())
+	require.NoError(t, err)
+	require.Len(t, paths, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	paths, err = GetModulePathsContext(ctx, tempDIR, ScanDeep())
+	require.ErrorIs(t, err, context.Canceled)
+	require.Empty(t, paths)
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	_, err = GetModulePathsContext(ctx, tempDIR, ScanDeep(), SkipNoGo())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// TestGetProjectRootContext tests context-aware project root detection
+// TestGetProjectRootContext 测试支持 context 的项目根目录发现
+func TestGetProjectRootContext(t *testing.T) {
+	pkgPath := runpath.PARENT.Path()
+
+	root, ok, err := GetProjectRootContext(context.Background(), pkgPath)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, runpath.PARENT.Up(1), root)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	root, ok, err = GetProjectRootContext(ctx, pkgPath)
+	require.ErrorIs(t, err, context.Canceled)
+	require.False(t, ok)
+	require.Empty(t, root)
+}
+
 // =====================================================
 // Test Helpers
 // 测试辅助函数